@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"zed-cli-win-unofficial/internal/clierr"
+	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/pathutil"
+	"zed-cli-win-unofficial/internal/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+func bookmarkCommand(zedArgs []string) *cli.Command {
+	return &cli.Command{
+		Name:        "bookmark",
+		Usage:       "Name projects and open them with `zed @name`",
+		Description: "A bookmark can hold several folders or files, which all open together, eg: `zed @platform` for a set of related repos.\n\nPowerShell reads @name as splatting, quote it there (`zed '@platform'`) or use `zed bookmark open platform`.",
+		Commands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Bookmark paths under a name (the current folder by default), adding to an existing bookmark",
+				ArgsUsage: "<name> [path...]",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					name := cmd.Args().First()
+					if name == "" {
+						utils.Error("Usage: zed bookmark add <name> [path...]")
+						return clierr.New(clierr.Usage, "a bookmark name is required")
+					}
+
+					paths := cmd.Args().Tail()
+					if len(paths) == 0 {
+						paths = []string{"."}
+					}
+
+					resolvedPaths := make([]string, 0, len(paths))
+					for _, path := range paths {
+						absPath, err := filepath.Abs(path)
+						if err != nil {
+							utils.Error(fmt.Sprintf("Invalid path: %v", err))
+							return clierr.Wrap(clierr.InvalidPath, err)
+						}
+
+						if _, err := pathutil.Stat(absPath); err != nil {
+							utils.Error(fmt.Sprintf("Invalid path: %v", err))
+							return clierr.Wrap(clierr.InvalidPath, err)
+						}
+
+						resolvedPaths = append(resolvedPaths, absPath)
+					}
+
+					// Bookmarks work without Zed configured, so only a missing config starts out empty.
+					// One that can't be read is left alone, saving over it would lose everything in it.
+					cfg, err := config.LoadConfig()
+					if errors.Is(err, os.ErrNotExist) {
+						cfg, err = &config.Config{}, nil
+					}
+
+					if err != nil {
+						utils.Error(fmt.Sprintf("Error loading config: %v", err))
+						return configLoadError(err)
+					}
+
+					if err := cfg.AddBookmark(name, resolvedPaths); err != nil {
+						utils.Error(err.Error())
+						return clierr.Wrap(clierr.Usage, err)
+					}
+
+					if err := config.SaveConfig(cfg); err != nil {
+						utils.Error(fmt.Sprintf("Error saving config: %v", err))
+						return clierr.Wrap(clierr.ConfigInvalid, err)
+					}
+
+					stored, bookmarked, _ := cfg.FindBookmark(name)
+					utils.Success(fmt.Sprintf("Bookmark '@%s' saved with %d path(s)", stored, len(bookmarked)))
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List the bookmarks and their paths",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cfg, err := config.LoadConfig()
+					if err != nil {
+						utils.Error(fmt.Sprintf("Error loading config: %v", err))
+						utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
+						return configLoadError(err)
+					}
+
+					if len(cfg.Bookmarks) == 0 {
+						utils.Infoln("ℹ️ No bookmarks yet.")
+						utils.Infoln("👉 Tip: Run `zed bookmark add <name> [path...]` to create one.")
+						return nil
+					}
+
+					names := make([]string, 0, len(cfg.Bookmarks))
+					for name := range cfg.Bookmarks {
+						names = append(names, name)
+					}
+
+					slices.SortFunc(names, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+
+					for _, name := range names {
+						utils.Info("@%s\n", name)
+						for _, path := range cfg.Bookmarks[name] {
+							utils.Info("    %s\n", path)
+						}
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "open",
+				Usage:     "Open every path of a bookmark, same as `zed @name` but without quoting it in PowerShell",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					name := cmd.Args().First()
+					if name == "" {
+						utils.Error("Usage: zed bookmark open <name>")
+						return clierr.New(clierr.Usage, "a bookmark name is required")
+					}
+
+					cfg, err := config.LoadConfig()
+					if err != nil {
+						utils.Error(fmt.Sprintf("Error loading config: %v", err))
+						utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
+						return configLoadError(err)
+					}
+
+					_, paths, ok := cfg.FindBookmark(name)
+					if !ok {
+						utils.Error(fmt.Sprintf("No bookmark named '%s'", config.BookmarkName(name)))
+						utils.Infoln("👉 Tip: Run `zed bookmark list` to see the bookmarks.")
+						return clierr.New(clierr.Usage, "no bookmark named '%s'", config.BookmarkName(name))
+					}
+
+					return launchProjects(ctx, cmd, zedArgs, paths, launchLimits{})
+				},
+			},
+			{
+				Name:      "remove",
+				Usage:     "Delete a bookmark",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return updateBookmarks(cmd, 1, func(cfg *config.Config, args []string) (string, error) {
+						if err := cfg.RemoveBookmark(args[0]); err != nil {
+							return "", err
+						}
+
+						return fmt.Sprintf("Bookmark '@%s' removed", config.BookmarkName(args[0])), nil
+					})
+				},
+			},
+			{
+				Name:      "rename",
+				Usage:     "Give a bookmark a new name",
+				ArgsUsage: "<name> <new-name>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return updateBookmarks(cmd, 2, func(cfg *config.Config, args []string) (string, error) {
+						if err := cfg.RenameBookmark(args[0], args[1]); err != nil {
+							return "", err
+						}
+
+						return fmt.Sprintf("Bookmark '@%s' renamed to '@%s'", config.BookmarkName(args[0]), config.BookmarkName(args[1])), nil
+					})
+				},
+			},
+		},
+	}
+}
+
+// updateBookmarks loads the config, applies a change using the first argCount arguments and saves it
+func updateBookmarks(cmd *cli.Command, argCount int, update func(cfg *config.Config, args []string) (string, error)) error {
+	args := cmd.Args().Slice()
+	if len(args) < argCount {
+		utils.Error(fmt.Sprintf("Usage: zed bookmark %s %s", cmd.Name, cmd.ArgsUsage))
+		return clierr.New(clierr.Usage, "expected %d argument(s), got %d", argCount, len(args))
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		utils.Error(fmt.Sprintf("Error loading config: %v", err))
+		utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
+		return configLoadError(err)
+	}
+
+	message, err := update(cfg, args[:argCount])
+	if err != nil {
+		utils.Error(err.Error())
+		return clierr.Wrap(clierr.Usage, err)
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		utils.Error(fmt.Sprintf("Error saving config: %v", err))
+		return clierr.Wrap(clierr.ConfigInvalid, err)
+	}
+
+	utils.Success(message)
+	return nil
+}
@@ -3,13 +3,20 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"zed-cli-win-unofficial/internal/clierr"
 	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/pathutil"
+	"zed-cli-win-unofficial/internal/process"
+	"zed-cli-win-unofficial/internal/project"
 	"zed-cli-win-unofficial/internal/utils"
 
 	"github.com/urfave/cli/v3"
 )
 
-func configCommand() *cli.Command {
+func configCommand(zedArgs []string) *cli.Command {
 	return &cli.Command{
 		Name:  "config",
 		Usage: "Configure the CLI's Path & Settings",
@@ -22,24 +29,28 @@ func configCommand() *cli.Command {
 
 					if path == "" {
 						utils.Error("No path provided.")
-						return nil
+						return clierr.New(clierr.Usage, "no path provided")
 					}
 
 					resolvedPath, err := config.ValidatePath(path)
 					if err != nil {
 						utils.PrintInvalidPathBanner()
 						utils.Error(fmt.Sprintf("Invalid path: %v", err))
-						return nil
+						return clierr.Wrap(clierr.InvalidPath, err)
 					}
 
-					cfg := &config.Config{
-						ZedPath:            resolvedPath,
-						ContextMenuEnabled: false,
+					// Keep the rest of an existing config (context menu state, default args, ...) intact.
+					cfg, err := config.LoadConfig()
+					if err != nil {
+						cfg = &config.Config{}
 					}
 
-					if err := config.SaveConfig(cfg); err != nil {
-						utils.Error(fmt.Sprintf("Error saving config: %v", err))
-						return nil
+					// A plain path replaces whichever named install was the default.
+					cfg.ZedPath = resolvedPath
+					cfg.DefaultInstall = ""
+
+					if err := saveConfig(cfg); err != nil {
+						return err
 					}
 
 					utils.Success(fmt.Sprintf("Zed path configured: %s", resolvedPath))
@@ -47,27 +58,398 @@ func configCommand() *cli.Command {
 					return nil
 				},
 			},
+			{
+				Name:  "detect",
+				Usage: "Search the usual install locations for Zed and pick the one to use",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					// Keep the rest of an existing config intact, same as `config set`.
+					cfg, _ := config.LoadConfig()
+
+					if _, err := detectZed(cfg); err != nil {
+						return err
+					}
+
+					utils.Infoln("💡 You may want to run `zed context install` to set up or update context menus.")
+					return nil
+				},
+			},
 			{
 				Name:  "get",
 				Usage: "Get the current path to the Zed executable",
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					cfg, err := config.LoadConfig()
+					cfg, err := loadConfig()
 					if err != nil {
-						utils.Error(fmt.Sprintf("Error loading config: %v", err))
-						utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
-						return nil
+						return err
 					}
 
 					if !config.FileExists(cfg.ZedPath) {
 						utils.Error("Configured Zed path no longer exists")
 						utils.Infoln("👉 Tip: Run `zed config set <path>` to update the path.")
-						return nil
+						return clierr.New(clierr.ZedNotFound, "configured Zed path does not exist: %s", cfg.ZedPath)
 					}
 
 					utils.Success(fmt.Sprintf("Zed is configured at: %s", cfg.ZedPath))
+
+					detected, err := process.DetectZedVersion(cfg.ZedPath, cfg.ZedVersion)
+					if err != nil {
+						utils.Warning(fmt.Sprintf("Could not determine Zed version: %v", err))
+						utils.Infoln("👉 Tip: Run `zed config version <version>` to set it manually.")
+						return nil
+					}
+
+					utils.Infoln(fmt.Sprintf("📦 Zed version: v%s (from %s)", detected.Version.String(), detected.Source))
+					return nil
+				},
+			},
+			{
+				Name:      "args",
+				Usage:     "Set the default arguments passed to zed.exe, eg: `zed config args -- --foreground`",
+				ArgsUsage: "[-- zed-args...]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "clear",
+						Usage: "Remove the default arguments",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cfg, err := loadConfig()
+					if err != nil {
+						return err
+					}
+
+					if !cmd.Bool("clear") && len(zedArgs) == 0 {
+						if len(cfg.ZedArgs) == 0 {
+							utils.Infoln("ℹ️ No default Zed arguments configured.")
+							return nil
+						}
+
+						utils.Success(fmt.Sprintf("Default Zed arguments: %s", strings.Join(cfg.ZedArgs, " ")))
+						return nil
+					}
+
+					cfg.ZedArgs = zedArgs
+					if cmd.Bool("clear") {
+						cfg.ZedArgs = nil
+					}
+
+					if err := saveConfig(cfg); err != nil {
+						return err
+					}
+
+					if len(cfg.ZedArgs) == 0 {
+						utils.Success("Default Zed arguments removed")
+						return nil
+					}
+
+					utils.Success(fmt.Sprintf("Default Zed arguments configured: %s", strings.Join(cfg.ZedArgs, " ")))
+					utils.Infoln("💡 Arguments given after `--` on the command line replace these defaults.")
+					return nil
+				},
+			},
+			{
+				Name:      "create-policy",
+				Usage:     "Set what happens when a launch path doesn't exist: always, ask or never",
+				ArgsUsage: "[always|ask|never]",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cfg, err := loadConfig()
+					if err != nil {
+						return err
+					}
+
+					if cmd.Args().Len() == 0 {
+						policy, err := process.ParseCreatePolicy(cfg.CreatePolicy)
+						if err != nil {
+							utils.Error(err.Error())
+							return clierr.Wrap(clierr.ConfigInvalid, err)
+						}
+
+						utils.Success(fmt.Sprintf("Create policy: %s", policy))
+						return nil
+					}
+
+					policy, err := process.ParseCreatePolicy(cmd.Args().First())
+					if err != nil {
+						utils.Error(err.Error())
+						return clierr.Wrap(clierr.Usage, err)
+					}
+
+					cfg.CreatePolicy = string(policy)
+					if err := saveConfig(cfg); err != nil {
+						return err
+					}
+
+					utils.Success(fmt.Sprintf("Create policy configured: %s", policy))
+					return nil
+				},
+			},
+			{
+				Name:      "version",
+				Usage:     "Override the detected Zed version, for builds without usable version info",
+				ArgsUsage: "[version]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "clear",
+						Usage: "Remove the override and detect the version again",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cfg, err := loadConfig()
+					if err != nil {
+						return err
+					}
+
+					if cmd.Bool("clear") {
+						cfg.ZedVersion = ""
+					} else if cmd.Args().Len() > 0 {
+						v, err := process.ParseZedVersion(cmd.Args().First())
+						if err != nil {
+							utils.Error(fmt.Sprintf("Invalid version: %v", err))
+							return clierr.Wrap(clierr.Usage, err)
+						}
+
+						cfg.ZedVersion = v.String()
+					} else {
+						detected, err := process.DetectZedVersion(cfg.ZedPath, cfg.ZedVersion)
+						if err != nil {
+							utils.Error(fmt.Sprintf("Could not determine Zed version: %v", err))
+							return clierr.Wrap(clierr.General, err)
+						}
+
+						utils.Success(fmt.Sprintf("Zed version: v%s (from %s)", detected.Version.String(), detected.Source))
+						return nil
+					}
+
+					if err := saveConfig(cfg); err != nil {
+						return err
+					}
+
+					if cfg.ZedVersion == "" {
+						utils.Success("Zed version override removed")
+						return nil
+					}
+
+					utils.Success(fmt.Sprintf("Zed version override configured: v%s", cfg.ZedVersion))
+					return nil
+				},
+			},
+			toggleCommand(
+				"root",
+				"Set whether `zed <file>` also opens the project root the file is in: on or off",
+				"Open project root",
+				"root",
+				func(cfg *config.Config) *bool { return &cfg.OpenProjectRoot },
+			),
+			toggleCommand(
+				"project-env",
+				"Set whether launches load the project's .env and the env and zedArgs of workspace files: on or off",
+				"Load project env",
+				"project-env",
+				func(cfg *config.Config) *bool { return &cfg.LoadProjectEnv },
+			),
+			{
+				Name:      "root-markers",
+				Usage:     "Set the files and folders that mark a project root, a trailing / means a folder",
+				ArgsUsage: "[marker...]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "clear",
+						Usage: "Go back to the default markers",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cfg, err := loadConfig()
+					if err != nil {
+						return err
+					}
+
+					if !cmd.Bool("clear") && cmd.Args().Len() == 0 {
+						markers := cfg.RootMarkers
+						if len(markers) == 0 {
+							markers = project.DefaultMarkers()
+						}
+
+						utils.Success(fmt.Sprintf("Project root markers: %s", strings.Join(markers, " ")))
+						return nil
+					}
+
+					cfg.RootMarkers = cmd.Args().Slice()
+					if cmd.Bool("clear") {
+						cfg.RootMarkers = nil
+					}
+
+					if err := saveConfig(cfg); err != nil {
+						return err
+					}
+
+					if len(cfg.RootMarkers) == 0 {
+						utils.Success(fmt.Sprintf("Project root markers reset to: %s", strings.Join(project.DefaultMarkers(), " ")))
+						return nil
+					}
+
+					utils.Success(fmt.Sprintf("Project root markers configured: %s", strings.Join(cfg.RootMarkers, " ")))
+					return nil
+				},
+			},
+			{
+				Name:      "wsl-distro",
+				Usage:     "Set the WSL distro Linux paths like /home/me/src belong to, when not run from inside WSL",
+				ArgsUsage: "[name]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "clear",
+						Usage: "Remove the configured distro",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cfg, err := loadConfig()
+					if err != nil {
+						return err
+					}
+
+					if !cmd.Bool("clear") && cmd.Args().Len() == 0 {
+						if cfg.WSLDistro == "" {
+							utils.Infoln("ℹ️ No WSL distro configured, Linux paths are only translated when run from inside WSL.")
+							return nil
+						}
+
+						utils.Success(fmt.Sprintf("WSL distro: %s", cfg.WSLDistro))
+						return nil
+					}
+
+					cfg.WSLDistro = cmd.Args().First()
+					if cmd.Bool("clear") {
+						cfg.WSLDistro = ""
+					}
+
+					if err := saveConfig(cfg); err != nil {
+						return err
+					}
+
+					if cfg.WSLDistro == "" {
+						utils.Success("WSL distro removed")
+						return nil
+					}
+
+					utils.Success(fmt.Sprintf("WSL distro configured: %s", cfg.WSLDistro))
+					return nil
+				},
+			},
+			{
+				Name:      "path-timeout",
+				Usage:     "Set how many seconds a path check may take before a network location counts as not responding",
+				ArgsUsage: "[seconds]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "clear",
+						Usage: "Go back to the default timeout",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cfg, err := loadConfig()
+					if err != nil {
+						return err
+					}
+
+					if cmd.Bool("clear") {
+						cfg.PathTimeoutSeconds = 0
+					} else if cmd.Args().Len() > 0 {
+						seconds, err := strconv.Atoi(cmd.Args().First())
+						if err != nil || seconds < 1 {
+							utils.Error(fmt.Sprintf("Invalid timeout '%s', expected a number of seconds", cmd.Args().First()))
+							return clierr.New(clierr.Usage, "invalid timeout '%s'", cmd.Args().First())
+						}
+
+						cfg.PathTimeoutSeconds = seconds
+					} else {
+						utils.Success(fmt.Sprintf("Path timeout: %s", pathTimeout(cfg)))
+						return nil
+					}
+
+					if err := saveConfig(cfg); err != nil {
+						return err
+					}
+
+					utils.Success(fmt.Sprintf("Path timeout configured: %s", pathTimeout(cfg)))
 					return nil
 				},
 			},
 		},
 	}
 }
+
+// toggleCommand builds a `zed config <name> [on|off]` subcommand for a setting that's on or off.
+// label names the setting in messages, flag is the launch flag that overrides it (eg: root for --root/--no-root).
+func toggleCommand(name string, usage string, label string, flag string, setting func(cfg *config.Config) *bool) *cli.Command {
+	return &cli.Command{
+		Name:      name,
+		Usage:     usage,
+		ArgsUsage: "[on|off]",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			switch strings.ToLower(cmd.Args().First()) {
+			case "":
+				utils.Success(fmt.Sprintf("%s: %s", label, onOff(*setting(cfg))))
+				return nil
+			case "on":
+				*setting(cfg) = true
+			case "off":
+				*setting(cfg) = false
+			default:
+				utils.Error(fmt.Sprintf("Unknown value '%s' (expected on or off)", cmd.Args().First()))
+				return clierr.New(clierr.Usage, "unknown value '%s'", cmd.Args().First())
+			}
+
+			if err := saveConfig(cfg); err != nil {
+				return err
+			}
+
+			utils.Success(fmt.Sprintf("%s configured: %s", label, onOff(*setting(cfg))))
+			utils.Infoln(fmt.Sprintf("💡 Use `--%s` or `--no-%s` to decide for a single launch.", flag, flag))
+			return nil
+		},
+	}
+}
+
+// loadConfig loads the config for a `zed config` subcommand, explaining what went wrong when it can't
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		utils.Error(fmt.Sprintf("Error loading config: %v", err))
+		utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
+		return nil, configLoadError(err)
+	}
+
+	return cfg, nil
+}
+
+// saveConfig saves the config changed by a `zed config` subcommand
+func saveConfig(cfg *config.Config) error {
+	if err := config.SaveConfig(cfg); err != nil {
+		utils.Error(fmt.Sprintf("Error saving config: %v", err))
+		return clierr.Wrap(clierr.ConfigInvalid, err)
+	}
+
+	return nil
+}
+
+// pathTimeout returns the configured path timeout, or the default one
+func pathTimeout(cfg *config.Config) time.Duration {
+	if cfg.PathTimeoutSeconds > 0 {
+		return time.Duration(cfg.PathTimeoutSeconds) * time.Second
+	}
+
+	return pathutil.DefaultTimeout
+}
+
+// onOff formats a setting for display
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+
+	return "off"
+}
@@ -3,11 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
+	"zed-cli-win-unofficial/internal/clierr"
 	"zed-cli-win-unofficial/internal/config"
 	"zed-cli-win-unofficial/internal/fileext"
+	"zed-cli-win-unofficial/internal/pathutil"
+	"zed-cli-win-unofficial/internal/protocol"
 	"zed-cli-win-unofficial/internal/registry"
 	"zed-cli-win-unofficial/internal/utils"
+	"zed-cli-win-unofficial/internal/workspace"
 
 	"github.com/urfave/cli/v3"
 )
@@ -28,22 +33,36 @@ func contextCommand() *cli.Command {
 					if err != nil {
 						fmt.Printf("❌ Error loading config: %v\n", err)
 						fmt.Println("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
-						return nil
+						return configLoadError(err)
 					}
 
 					if !config.FileExists(cfg.ZedPath) {
 						fmt.Printf("❌ Configured Zed path does not exist: %s\n", cfg.ZedPath)
 						fmt.Println("👉 Tip: Run `zed config set <path>` to update the path.")
-						return nil
+						return clierr.New(clierr.ZedNotFound, "configured Zed path does not exist: %s", cfg.ZedPath)
+					}
+
+					launcherPath, err := os.Executable()
+					if err != nil {
+						utils.Error(fmt.Sprintf("Unable to locate the CLI executable: %v", err))
+						return clierr.Wrap(clierr.General, err)
 					}
 
-					registryCfg := registry.NewConfig(cfg.ZedPath, fileext.SupportedExtensions())
+					registryCfg := registry.NewConfig(cfg.ZedPath, append(fileext.SupportedExtensions(), workspace.Extension))
+					registryCfg.LauncherPath = pathutil.Display(launcherPath)
+					registryCfg.LauncherExtensions = []string{workspace.Extension}
+					registryCfg.URLScheme = protocol.Scheme
+
+					dryRun := cmd.Bool("dry-run")
+					if dryRun {
+						registryCfg.Backend = registry.NewDryRunBackend(os.Stdout)
+					}
 
 					utils.Debugln("🚀 Setting up Zed context menu and file associations...")
 
 					if err := registry.InstallGenericContextMenu(registryCfg); err != nil {
 						utils.Error(fmt.Sprintf("Failed to install context menu: %v", err))
-						return nil
+						return clierr.Wrap(clierr.RegistryFailure, err)
 					}
 
 					for _, ext := range registryCfg.FileExtensions {
@@ -57,17 +76,30 @@ func contextCommand() *cli.Command {
 							continue
 						}
 
-						progID := fmt.Sprintf("%s%s", registryCfg.AppName, ext)
-						if err := registry.AssociateExtensionWithProgID(ext, progID); err != nil {
+						progID := registryCfg.ProgID(ext)
+						if err := registry.AssociateExtensionWithProgID(registryCfg, ext, progID); err != nil {
 							utils.Error(fmt.Sprintf("Failed to associate %s files with Zed: %v", ext, err))
-							return nil
+							return clierr.Wrap(clierr.RegistryFailure, err)
+						}
+
+						// Nothing else opens workspace files, so double-clicking one should just work.
+						if ext == workspace.Extension {
+							if err := registry.SetDefaultProgID(registryCfg, ext, progID); err != nil {
+								utils.Error(err.Error())
+								return clierr.Wrap(clierr.RegistryFailure, err)
+							}
 						}
 					}
 
+					if dryRun {
+						utils.Infoln("ℹ️ Dry run: the registry and config were not changed.")
+						return nil
+					}
+
 					cfg.ContextMenuEnabled = true
 					if err := config.SaveConfig(cfg); err != nil {
 						utils.Error(fmt.Sprintf("Error saving config: %v", err))
-						return nil
+						return clierr.Wrap(clierr.ConfigInvalid, err)
 					}
 
 					utils.PrintContextInstallBanner()
@@ -88,7 +120,7 @@ func contextCommand() *cli.Command {
 
 					if err != nil {
 						fmt.Printf("❌ Error loading config: %v\n", err)
-						return nil
+						return configLoadError(err)
 					}
 
 					if !cfg.ContextMenuEnabled {
@@ -97,19 +129,30 @@ func contextCommand() *cli.Command {
 						return nil
 					}
 
-					registryConfig := registry.NewConfig("", fileext.SupportedExtensions())
+					registryConfig := registry.NewConfig("", append(fileext.SupportedExtensions(), workspace.Extension))
+					registryConfig.URLScheme = protocol.Scheme
+
+					dryRun := cmd.Bool("dry-run")
+					if dryRun {
+						registryConfig.Backend = registry.NewDryRunBackend(os.Stdout)
+					}
 
 					utils.Debugln("🧹 Removing Zed context menu and file associations...")
 
 					if err := registry.UninstallAllContextMenus(registryConfig); err != nil {
 						utils.Error(fmt.Sprintf("Failed to remove context menu: %v", err))
+						return clierr.Wrap(clierr.RegistryFailure, err)
+					}
+
+					if dryRun {
+						utils.Infoln("ℹ️ Dry run: the registry and config were not changed.")
 						return nil
 					}
 
 					cfg.ContextMenuEnabled = false
 					if err := config.SaveConfig(cfg); err != nil {
 						utils.Error(fmt.Sprintf("Error saving config: %v", err))
-						return nil
+						return clierr.Wrap(clierr.ConfigInvalid, err)
 					}
 
 					utils.PrintContextUninstallBanner()
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"zed-cli-win-unofficial/internal/clierr"
+	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/discovery"
+	"zed-cli-win-unofficial/internal/process"
+	"zed-cli-win-unofficial/internal/utils"
+)
+
+// detectZed searches the machine for Zed, lets the user pick one of the installs found and saves it as the configured path.
+// cfg is the config to update, nil starts a new one.
+func detectZed(cfg *config.Config) (*config.Config, error) {
+	utils.Infoln("🔍 Searching for Zed installations...")
+
+	candidates := discovery.NewFinder().Find()
+	if len(candidates) == 0 {
+		utils.Error("No Zed installation found")
+		utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
+		return nil, clierr.New(clierr.ZedNotFound, "no Zed installation found")
+	}
+
+	for i, candidate := range candidates {
+		versionText := "unknown version"
+		if v, err := process.GetZedVersion(candidate.Path); err == nil {
+			versionText = "v" + v.String()
+		}
+
+		utils.Info("  %d. %s (%s, %s)\n", i+1, candidate.Path, versionText, candidate.Source)
+	}
+
+	choice := 1
+	if len(candidates) > 1 {
+		answer := utils.Prompt(fmt.Sprintf("Which one should be used? [1-%d, default 1]", len(candidates)))
+		if answer != "" {
+			n, err := strconv.Atoi(answer)
+			if err != nil || n < 1 || n > len(candidates) {
+				utils.Error(fmt.Sprintf("Invalid choice: %s", answer))
+				return nil, clierr.New(clierr.Usage, "invalid choice: %s", answer)
+			}
+
+			choice = n
+		}
+	} else if !utils.Confirm(fmt.Sprintf("Use %s?", candidates[0].Path)) {
+		return nil, clierr.New(clierr.ZedNotFound, "no Zed installation selected")
+	}
+
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	cfg.ZedPath = candidates[choice-1].Path
+	cfg.DefaultInstall = ""
+
+	if err := config.SaveConfig(cfg); err != nil {
+		utils.Error(fmt.Sprintf("Error saving config: %v", err))
+		return nil, clierr.Wrap(clierr.ConfigInvalid, err)
+	}
+
+	utils.Success(fmt.Sprintf("Zed path configured: %s", cfg.ZedPath))
+	return cfg, nil
+}
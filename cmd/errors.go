@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"zed-cli-win-unofficial/internal/clierr"
+
+	"github.com/urfave/cli/v3"
+)
+
+// configLoadError maps a config.LoadConfig failure to its exit code
+func configLoadError(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return clierr.Wrap(clierr.ConfigMissing, err)
+	}
+
+	return clierr.Wrap(clierr.ConfigInvalid, err)
+}
+
+// exitErrHandler exits with the code of a clierr.Error without printing it again, the actions already explained what went wrong.
+// Every other error gets urfave/cli's default handling.
+func exitErrHandler(ctx context.Context, cmd *cli.Command, err error) {
+	var cliErr *clierr.Error
+	if errors.As(err, &cliErr) {
+		cli.OsExiter(cliErr.ExitCode())
+		return
+	}
+
+	cli.HandleExitCoder(err)
+}
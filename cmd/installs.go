@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"zed-cli-win-unofficial/internal/clierr"
+	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/process"
+	"zed-cli-win-unofficial/internal/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+func installsCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "installs",
+		Usage:       "Manage multiple Zed installs (stable, preview, dev builds)",
+		Description: "Register several Zed executables by name and pick one per launch with `zed --install <name>` or `zed --channel <channel>`.",
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List the registered Zed installs",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					cfg, err := config.LoadConfig()
+					if err != nil {
+						utils.Error(fmt.Sprintf("Error loading config: %v", err))
+						utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
+						return configLoadError(err)
+					}
+
+					if len(cfg.Installs) == 0 {
+						utils.Infoln("ℹ️ No Zed installs registered.")
+						utils.Infoln("👉 Tip: Run `zed installs add <name> <path>` to register one.")
+						return nil
+					}
+
+					for _, install := range cfg.Installs {
+						marker := " "
+						if strings.EqualFold(install.Name, cfg.DefaultInstall) {
+							marker = "*"
+						}
+
+						installVersion := install.Version
+						if installVersion == "" {
+							installVersion = "unknown"
+						}
+
+						utils.Info("%s %-12s %-8s v%-12s %s\n", marker, install.Name, install.Channel, installVersion, install.Path)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "add",
+				Usage:     "Register a Zed install under a name",
+				ArgsUsage: "<name> <path>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "channel",
+						Usage: "Release `CHANNEL` of the install: stable, preview, nightly or dev (guessed from the path by default)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					name, path := cmd.Args().Get(0), cmd.Args().Get(1)
+					if name == "" || path == "" {
+						utils.Error("Usage: zed installs add <name> <path>")
+						return clierr.New(clierr.Usage, "name and path are required")
+					}
+
+					// Only a missing config starts out empty, one that can't be read is left alone
+					// rather than saved over with nothing but this install in it.
+					cfg, err := config.LoadConfig()
+					if errors.Is(err, os.ErrNotExist) {
+						cfg, err = &config.Config{}, nil
+					}
+
+					if err != nil {
+						utils.Error(fmt.Sprintf("Error loading config: %v", err))
+						return configLoadError(err)
+					}
+
+					resolvedPath, err := config.ValidatePath(path)
+					if err != nil {
+						utils.PrintInvalidPathBanner()
+						utils.Error(fmt.Sprintf("Invalid path: %v", err))
+						return clierr.Wrap(clierr.InvalidPath, err)
+					}
+
+					channel := config.GuessChannel(resolvedPath)
+					if cmd.IsSet("channel") {
+						if channel, err = config.ParseChannel(cmd.String("channel")); err != nil {
+							utils.Error(err.Error())
+							return clierr.Wrap(clierr.Usage, err)
+						}
+					}
+
+					install := config.Install{Name: name, Path: resolvedPath, Channel: channel}
+
+					if zedVersion, err := process.GetZedVersion(resolvedPath); err != nil {
+						utils.Warning(fmt.Sprintf("Could not determine Zed version: %v", err))
+					} else {
+						install.Version = zedVersion.String()
+					}
+
+					if err := cfg.AddInstall(install); err != nil {
+						utils.Error(err.Error())
+						return clierr.Wrap(clierr.Usage, err)
+					}
+
+					// Becomes the default when no Zed is configured yet, or when it's the Zed that already is.
+					if cfg.ZedPath == "" || cfg.DefaultInstall == "" && strings.EqualFold(cfg.ZedPath, resolvedPath) {
+						cfg.SetDefaultInstall(name)
+					}
+
+					if err := config.SaveConfig(cfg); err != nil {
+						utils.Error(fmt.Sprintf("Error saving config: %v", err))
+						return clierr.Wrap(clierr.ConfigInvalid, err)
+					}
+
+					utils.Success(fmt.Sprintf("Zed install '%s' (%s) registered: %s", name, channel, resolvedPath))
+					return nil
+				},
+			},
+			{
+				Name:      "remove",
+				Usage:     "Forget a registered Zed install",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return updateInstalls(cmd, func(cfg *config.Config, name string) (string, error) {
+						if err := cfg.RemoveInstall(name); err != nil {
+							return "", err
+						}
+
+						return fmt.Sprintf("Zed install '%s' removed", name), nil
+					})
+				},
+			},
+			{
+				Name:      "default",
+				Usage:     "Make a registered install the one plain `zed` launches",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return updateInstalls(cmd, func(cfg *config.Config, name string) (string, error) {
+						if err := cfg.SetDefaultInstall(name); err != nil {
+							return "", err
+						}
+
+						return fmt.Sprintf("Zed install '%s' is now the default: %s", cfg.DefaultInstall, cfg.ZedPath), nil
+					})
+				},
+			},
+		},
+	}
+}
+
+// updateInstalls loads the config, applies a change to the install named in the first argument and saves it
+func updateInstalls(cmd *cli.Command, update func(cfg *config.Config, name string) (string, error)) error {
+	name := cmd.Args().First()
+	if name == "" {
+		utils.Error("No install name provided.")
+		return clierr.New(clierr.Usage, "no install name provided")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		utils.Error(fmt.Sprintf("Error loading config: %v", err))
+		utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
+		return configLoadError(err)
+	}
+
+	message, err := update(cfg, name)
+	if err != nil {
+		utils.Error(err.Error())
+		return clierr.Wrap(clierr.Usage, err)
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		utils.Error(fmt.Sprintf("Error saving config: %v", err))
+		return clierr.Wrap(clierr.ConfigInvalid, err)
+	}
+
+	utils.Success(message)
+	return nil
+}
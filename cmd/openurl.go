@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"zed-cli-win-unofficial/internal/clierr"
+	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/protocol"
+	"zed-cli-win-unofficial/internal/utils"
+	"zed-cli-win-unofficial/internal/workspace"
+
+	"github.com/urfave/cli/v3"
+)
+
+// openURLCommand handles `zed-cli://open?path=...` links, Windows runs it when such a link is clicked
+func openURLCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "open-url",
+		Usage:     "Open a zed-cli:// link (used by the registered URL protocol)",
+		ArgsUsage: "<link>",
+		Hidden:    true,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				utils.Error("Usage: zed open-url <link>")
+				return clierr.New(clierr.Usage, "expected exactly one link, got %d", cmd.Args().Len())
+			}
+
+			link, err := protocol.Parse(cmd.Args().First())
+			if err != nil {
+				utils.Error(fmt.Sprintf("Refusing to open link: %v", err))
+				return clierr.Wrap(clierr.Usage, err)
+			}
+
+			// Links only open what's already there: nothing gets created, and workspace files are
+			// refused because they carry their own Zed arguments and environment.
+			if !config.FileExists(link.Path) {
+				utils.Error(fmt.Sprintf("Linked path does not exist: %s", link.Path))
+				return clierr.New(clierr.InvalidPath, "linked path does not exist: %s", link.Path)
+			}
+
+			if workspace.IsWorkspaceFile(link.Path) {
+				utils.Error("Workspace files can't be opened from links")
+				return clierr.New(clierr.Usage, "workspace files can't be opened from links")
+			}
+
+			// Links never create paths or expand wildcards, whatever the flags and config say.
+			utils.Debug("Opening link target %s\n", link.Target())
+			return launchProjects(ctx, cmd, nil, []string{link.Target()}, launchLimits{noCreate: true, noGlob: true})
+		},
+	}
+}
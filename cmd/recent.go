@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"zed-cli-win-unofficial/internal/clierr"
+	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/history"
+	"zed-cli-win-unofficial/internal/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+func recentCommand(zedArgs []string) *cli.Command {
+	return &cli.Command{
+		Name:        "recent",
+		Usage:       "List and reopen recently opened projects",
+		Description: "Every path opened through the CLI is remembered, most recent first. Global flags such as `--new` still apply, eg: `zed --new recent open 2`.",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			entries, err := history.Load(config.HistoryPath())
+			if err != nil {
+				utils.Error(err.Error())
+				return clierr.Wrap(clierr.General, err)
+			}
+
+			if len(entries) == 0 {
+				utils.Infoln("ℹ️ No recent projects yet.")
+				return nil
+			}
+
+			for i, entry := range entries {
+				utils.Info("%3d. %s  %s\n", i+1, entry.OpenedAt.Local().Format("2006-01-02 15:04"), entry.Path)
+			}
+
+			return nil
+		},
+		Commands: []*cli.Command{
+			{
+				Name:      "open",
+				Usage:     "Reopen a recent project by its number or part of its path",
+				ArgsUsage: "<n|substring>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					query := cmd.Args().First()
+					if query == "" {
+						utils.Error("Usage: zed recent open <n|substring>")
+						return clierr.New(clierr.Usage, "a number or part of a path is required")
+					}
+
+					entries, err := history.Load(config.HistoryPath())
+					if err != nil {
+						utils.Error(err.Error())
+						return clierr.Wrap(clierr.General, err)
+					}
+
+					entry, err := findRecent(entries, query)
+					if err != nil {
+						utils.Error(err.Error())
+						utils.Infoln("👉 Tip: Run `zed recent` to see the recent projects.")
+						return clierr.Wrap(clierr.Usage, err)
+					}
+
+					if !config.FileExists(entry.Path) {
+						utils.Error(fmt.Sprintf("Recent project no longer exists: %s", entry.Path))
+						return clierr.New(clierr.InvalidPath, "recent project no longer exists: %s", entry.Path)
+					}
+
+					return launchProjects(ctx, cmd, zedArgs, []string{entry.Path}, launchLimits{})
+				},
+			},
+			{
+				Name:  "clear",
+				Usage: "Forget all recent projects",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if err := history.Clear(config.HistoryPath()); err != nil {
+						utils.Error(err.Error())
+						return clierr.Wrap(clierr.General, err)
+					}
+
+					utils.Success("Recent projects cleared")
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// findRecent picks an entry by its 1-based number, or else the most recent one whose path contains the query
+func findRecent(entries []history.Entry, query string) (history.Entry, error) {
+	if n, err := strconv.Atoi(query); err == nil {
+		if n < 1 || n > len(entries) {
+			return history.Entry{}, fmt.Errorf("no recent project number %d", n)
+		}
+
+		return entries[n-1], nil
+	}
+
+	for _, entry := range entries {
+		if strings.Contains(strings.ToLower(entry.Path), strings.ToLower(query)) {
+			return entry, nil
+		}
+	}
+
+	return history.Entry{}, fmt.Errorf("no recent project matches %q", query)
+}
@@ -2,16 +2,27 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"zed-cli-win-unofficial/internal/clierr"
 	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/environ"
+	"zed-cli-win-unofficial/internal/glob"
+	"zed-cli-win-unofficial/internal/pathutil"
 	"zed-cli-win-unofficial/internal/process"
+	"zed-cli-win-unofficial/internal/project"
 	"zed-cli-win-unofficial/internal/utils"
+	"zed-cli-win-unofficial/internal/workspace"
 
 	"github.com/urfave/cli/v3"
 )
 
 func Execute(ctx context.Context) error {
+	args, zedArgs := splitPassthroughArgs(os.Args)
+
 	cli.VersionPrinter = func(cmd *cli.Command) {
 		fmt.Println("v1.0.0")
 	}
@@ -28,38 +39,583 @@ func Execute(ctx context.Context) error {
 		Authors: []any{
 			"SameerJS6 <contact@sameerjs.com>",
 		},
-		Copyright:   "Copyright (c) 2025 SameerJS6",
-		ArgsUsage:   "[project-path]",
-		UsageText:   "zed [global options] [project-path]\n   zed [global options] command [command options] [arguments...]",
-		Category:    "Development Tools",
-		Suggest:     true,
-		HideHelp:    false,
-		HideVersion: false,
+		Copyright:      "Copyright (c) 2025 SameerJS6",
+		ArgsUsage:      "[project-path... | -]",
+		UsageText:      "zed [global options] [project-path...] [-- zed-args...]\n   zed [global options] command [command options] [arguments...]",
+		Category:       "Development Tools",
+		Suggest:        true,
+		HideHelp:       false,
+		HideVersion:    false,
+		ExitErrHandler: exitErrHandler,
 		Commands: []*cli.Command{
-			configCommand(),
+			configCommand(zedArgs),
 			contextCommand(),
+			installsCommand(),
+			recentCommand(zedArgs),
+			bookmarkCommand(zedArgs),
+			openURLCommand(),
+		},
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "wait",
+				Aliases: []string{"w"},
+				Usage:   "Wait for Zed to close before returning, for use as $EDITOR or GIT_EDITOR",
+			},
+			&cli.BoolFlag{
+				Name:    "new",
+				Aliases: []string{"n"},
+				Usage:   "Open the paths in a new window",
+			},
+			&cli.BoolFlag{
+				Name:    "add",
+				Aliases: []string{"a"},
+				Usage:   "Add the paths to the workspace of the current window",
+			},
+			&cli.BoolFlag{
+				Name:    "reuse",
+				Aliases: []string{"r"},
+				Usage:   "Replace the workspace of the current window with the paths",
+			},
+			&cli.BoolFlag{
+				Name:    "diff",
+				Aliases: []string{"d"},
+				Usage:   "Show two files side by side in Zed's diff view",
+			},
+			&cli.BoolWithInverseFlag{
+				Name:  "create",
+				Usage: "Create paths that don't exist yet without asking (--no-create refuses to)",
+			},
+			&cli.BoolFlag{
+				Name:    "parents",
+				Aliases: []string{"p"},
+				Usage:   "Also create missing parent folders of new paths",
+			},
+			&cli.StringSliceFlag{
+				Name:    "env",
+				Aliases: []string{"e"},
+				Usage:   "Set an environment variable for Zed as `KEY=VALUE`, can be repeated",
+			},
+			&cli.StringSliceFlag{
+				Name:  "env-file",
+				Usage: "Load environment variables for Zed from a .env style `FILE`, can be repeated",
+			},
+			&cli.BoolWithInverseFlag{
+				Name:  "project-env",
+				Usage: "Load the .env of the project being opened when no --env-file is given, and the env and zedArgs of workspace files (--no-project-env skips them)",
+			},
+			&cli.StringFlag{
+				Name:  "env-from-script",
+				Usage: "Run a setup `SCRIPT` (eg: VsDevCmd.bat) and pass the environment it produces to Zed",
+			},
+			&cli.StringFlag{
+				Name:  "install",
+				Usage: "Launch the registered Zed install with this `NAME` (see `zed installs list`)",
+			},
+			&cli.StringFlag{
+				Name:  "channel",
+				Usage: "Launch a registered Zed install of this `CHANNEL`: stable, preview, nightly or dev",
+			},
+			&cli.BoolWithInverseFlag{
+				Name:  "root",
+				Usage: "Open a file together with the root of the project it's in (--no-root opens just the file)",
+			},
+			&cli.BoolFlag{
+				Name:  "no-glob",
+				Usage: "Take wildcards (* ? [ ] { }) in paths literally instead of expanding them",
+			},
+			&cli.BoolFlag{
+				Name:  "attach",
+				Usage: "Keep Zed attached to this terminal and print its output here, for debugging",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Print what a launch or `context install/uninstall` would do instead of doing it",
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			cfg, err := config.LoadConfig()
+			projectPaths, err := resolveStdinArgs(cmd.Args().Slice(), cmd.Bool("dry-run"))
 			if err != nil {
-				utils.PrintZedNotFoundBanner("")
-				utils.Error(fmt.Sprintf("Error loading config: %v", err))
-				utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
-				return nil
+				utils.Error(err.Error())
+				return clierr.Wrap(clierr.Usage, err)
 			}
 
-			if !config.FileExists(cfg.ZedPath) {
-				utils.PrintZedNotFoundBanner("")
-				utils.Error(fmt.Sprintf("Configured Zed path does not exist: %s", cfg.ZedPath))
-				utils.Infoln("👉 Tip: Run `zed config set <path>` to update the path.")
+			return launchProjects(ctx, cmd, zedArgs, projectPaths, launchLimits{})
+		},
+	}
+
+	return app.Run(ctx, args)
+}
+
+// launchLimits narrows what a launch may do, whatever the flags and config say
+type launchLimits struct {
+	// noCreate refuses to create paths that don't exist
+	noCreate bool
+	// noGlob takes wildcard characters in paths literally
+	noGlob bool
+}
+
+// launchProjects opens the paths in the Zed picked by the global flags and config.
+// It's shared by the root command and the commands that reopen saved projects, eg: `zed recent open`.
+func launchProjects(ctx context.Context, cmd *cli.Command, zedArgs []string, projectPaths []string, limits launchLimits) error {
+	cfg, err := config.LoadConfig()
+
+	// On first run we look for Zed ourselves, as long as someone is there to confirm the pick.
+	// Detection saves the config, so a dry run leaves it alone.
+	if errors.Is(err, os.ErrNotExist) && !process.StdinIsPiped() && !cmd.Bool("dry-run") {
+		utils.Warning("Zed isn't configured yet")
+		if detected, detectErr := detectZed(nil); detectErr == nil {
+			cfg, err = detected, nil
+		}
+	}
+
+	if err != nil {
+		utils.PrintZedNotFoundBanner("")
+		utils.Error(fmt.Sprintf("Error loading config: %v", err))
+		utils.Infoln("👉 Tip: Run `zed config set <path>` to configure the Zed executable path.")
+		return configLoadError(err)
+	}
+
+	pathutil.SetTimeout(pathTimeout(cfg))
+
+	// Paths from Git Bash, Cygwin and WSL are made Windows paths before anything else looks at them.
+	projectPaths = process.TranslatePaths(projectPaths, wslDistro(cfg))
+
+	// cmd.exe and PowerShell hand wildcards over as they are, so we expand them ourselves.
+	if !cmd.Bool("no-glob") && !limits.noGlob {
+		if projectPaths, err = expandGlobs(ctx, projectPaths); err != nil {
+			utils.Error(err.Error())
+			return clierr.Wrap(clierr.InvalidPath, err)
+		}
+	}
+
+	projectPaths, err = expandBookmarks(cfg, projectPaths)
+	if err != nil {
+		utils.Error(err.Error())
+		utils.Infoln("👉 Tip: Run `zed bookmark list` to see the bookmarks.")
+		return clierr.Wrap(clierr.Usage, err)
+	}
 
-				return nil
+	projectPaths, workspaces, err := expandWorkspaces(projectPaths)
+	if err != nil {
+		utils.Error(err.Error())
+		return clierr.Wrap(clierr.InvalidPath, err)
+	}
+
+	// Diffs compare two loose files, a project root would only get in the way.
+	if openProjectRoot(cmd, cfg) && !cmd.Bool("diff") {
+		projectPaths = withProjectRoots(ctx, projectPaths, cfg.RootMarkers)
+	}
+
+	zedPath, err := cfg.ResolveZedPath(cmd.String("install"), cmd.String("channel"))
+	if err != nil {
+		utils.Error(err.Error())
+		utils.Infoln("👉 Tip: Run `zed installs list` to see the registered installs.")
+		return clierr.Wrap(clierr.ZedNotFound, err)
+	}
+
+	if _, err := pathutil.Probe(ctx, zedPath); errors.Is(err, pathutil.ErrNotResponding) {
+		utils.Error(fmt.Sprintf("Unable to reach Zed: %v", err))
+		if pathutil.IsNetworkPath(zedPath) {
+			utils.Infoln("👉 Tip: Reconnect the network drive, or raise the limit with `zed config path-timeout <seconds>`.")
+		} else {
+			utils.Infoln("👉 Tip: Check that the drive is connected, or raise the limit with `zed config path-timeout <seconds>`.")
+		}
+		return clierr.Wrap(clierr.ZedNotFound, err)
+	} else if err != nil {
+		utils.PrintZedNotFoundBanner("")
+		utils.Error(fmt.Sprintf("Configured Zed path does not exist: %s", zedPath))
+		utils.Infoln("👉 Tip: Run `zed config set <path>` to update the path.")
+
+		return clierr.New(clierr.ZedNotFound, "configured Zed path does not exist: %s", zedPath)
+	}
+
+	// The version override describes the configured Zed, not the other installs.
+	versionOverride := ""
+	if zedPath == cfg.ZedPath {
+		versionOverride = cfg.ZedVersion
+	}
+
+	windowMode, err := windowModeFromFlags(cmd)
+	if err != nil {
+		utils.Error(err.Error())
+		return clierr.Wrap(clierr.Usage, err)
+	}
+
+	// Window flags on the command line win over the window a workspace asks for.
+	for _, ws := range workspaces {
+		if windowMode == process.WindowDefault && ws.Window != "" {
+			windowMode = windowModes[ws.Window]
+		}
+	}
+
+	createPolicy, err := createPolicyFromFlags(cmd, cfg)
+	if err != nil {
+		utils.Error(err.Error())
+		return clierr.Wrap(clierr.ConfigInvalid, err)
+	}
+
+	if limits.noCreate {
+		createPolicy = process.CreateNever
+	}
+
+	env, err := launchEnv(ctx, cmd, cfg, projectPaths)
+	if err != nil {
+		utils.Error(err.Error())
+		return clierr.Wrap(clierr.Usage, err)
+	}
+
+	// Workspace env goes first so anything given on the command line overrides it.
+	// Like a project's .env, a workspace file comes with the repository, so its env and zedArgs need the same opt-in.
+	workspaceEnv := []string{}
+	workspaceArgs := []string{}
+	for _, ws := range workspaces {
+		if !loadProjectEnv(cmd, cfg) {
+			if len(ws.Env) > 0 || len(ws.ZedArgs) > 0 {
+				utils.Warning(fmt.Sprintf("Ignoring the env and zedArgs of %s", ws.Path))
+				utils.Infoln("👉 Tip: Use them with `--project-env`, or always with `zed config project-env on`.")
 			}
 
-			projectPath := cmd.Args().First()
-			return process.LaunchZed(cfg.ZedPath, projectPath)
-		},
+			continue
+		}
+
+		workspaceEnv = append(workspaceEnv, ws.EnvAssignments()...)
+		workspaceArgs = append(workspaceArgs, ws.ZedArgs...)
+	}
+
+	env = append(workspaceEnv, env...)
+
+	// Arguments after `--` win over the workspace's, which win over the per-user defaults, they are never merged.
+	extraArgs := zedArgs
+	if len(extraArgs) == 0 {
+		extraArgs = workspaceArgs
+	}
+
+	if len(extraArgs) == 0 {
+		extraArgs = cfg.ZedArgs
+	}
+
+	opts := process.LaunchOptions{
+		Wait:             cmd.Bool("wait"),
+		Window:           windowMode,
+		Diff:             cmd.Bool("diff"),
+		ExtraArgs:        extraArgs,
+		Create:           createPolicy,
+		CreateParents:    cmd.Bool("parents"),
+		VersionOverride:  versionOverride,
+		Env:              env,
+		EnvScript:        cmd.String("env-from-script"),
+		Attach:           cmd.Bool("attach"),
+		LogPath:          config.LogPath(),
+		HistoryPath:      config.HistoryPath(),
+		VersionCachePath: config.VersionCachePath(),
+		DryRun:           cmd.Bool("dry-run"),
+	}
+
+	return launchError(process.LaunchZed(ctx, zedPath, projectPaths, opts))
+}
+
+// maxGlobMatches is how many paths a single pattern may open before asking first
+const maxGlobMatches = 50
+
+// expandGlobs replaces arguments with wildcards by the paths they match.
+// An existing path is never expanded, so names like `notes[1].md` still open as they are.
+func expandGlobs(ctx context.Context, projectPaths []string) ([]string, error) {
+	expanded := make([]string, 0, len(projectPaths))
+
+	for _, projectPath := range projectPaths {
+		if !glob.HasMeta(projectPath) {
+			expanded = append(expanded, projectPath)
+			continue
+		}
+
+		if _, err := pathutil.Probe(ctx, projectPath); err == nil {
+			expanded = append(expanded, projectPath)
+			continue
+		} else if errors.Is(err, pathutil.ErrNotResponding) {
+			return nil, err
+		}
+
+		matches, err := glob.Expand(ctx, projectPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to expand %s: %w", projectPath, err)
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("nothing matches %s (use --no-glob to open it as a literal name)", projectPath)
+		}
+
+		// Without a terminal to ask on, too many matches is a refusal rather than a surprise.
+		if len(matches) > maxGlobMatches &&
+			(process.StdinIsPiped() || !utils.Confirm(fmt.Sprintf("%s matches %d paths, open all of them?", projectPath, len(matches)))) {
+			return nil, fmt.Errorf("%s matches %d paths, more than %d", projectPath, len(matches), maxGlobMatches)
+		}
+
+		utils.Debug("Expanded %s to %d paths\n", projectPath, len(matches))
+		expanded = append(expanded, matches...)
+	}
+
+	return expanded, nil
+}
+
+// expandBookmarks replaces `@name` targets with the paths of that bookmark.
+// An existing file or folder whose name starts with @ is left alone.
+func expandBookmarks(cfg *config.Config, projectPaths []string) ([]string, error) {
+	expanded := make([]string, 0, len(projectPaths))
+
+	for _, projectPath := range projectPaths {
+		if !strings.HasPrefix(projectPath, config.BookmarkPrefix) || config.FileExists(projectPath) {
+			expanded = append(expanded, projectPath)
+			continue
+		}
+
+		_, paths, ok := cfg.FindBookmark(projectPath)
+		if !ok {
+			return nil, fmt.Errorf("no bookmark named '%s'", config.BookmarkName(projectPath))
+		}
+
+		expanded = append(expanded, paths...)
+	}
+
+	return expanded, nil
+}
+
+// expandWorkspaces replaces existing .zed-workspace files with the folders and files they list.
+// A workspace file that doesn't exist yet is left alone, so it can be created and edited like any other file.
+func expandWorkspaces(projectPaths []string) ([]string, []*workspace.Workspace, error) {
+	expanded := make([]string, 0, len(projectPaths))
+	workspaces := []*workspace.Workspace{}
+
+	for _, projectPath := range projectPaths {
+		if !workspace.IsWorkspaceFile(projectPath) || !config.FileExists(projectPath) {
+			expanded = append(expanded, projectPath)
+			continue
+		}
+
+		ws, err := workspace.Load(projectPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		utils.Debug("Workspace %s lists %d folders and %d files\n", projectPath, len(ws.Folders), len(ws.Files))
+		expanded = append(expanded, ws.Targets()...)
+		workspaces = append(workspaces, ws)
+	}
+
+	return expanded, workspaces, nil
+}
+
+// openProjectRoot decides whether files open with their project root, --root/--no-root override the config
+func openProjectRoot(cmd *cli.Command, cfg *config.Config) bool {
+	if cmd.IsSet("root") {
+		return cmd.Bool("root")
+	}
+
+	return cfg.OpenProjectRoot
+}
+
+// withProjectRoots puts the project root of every file target in front of the targets, so Zed opens the file
+// inside its project. Roots already given as targets, or shared by several files, are only added once.
+func withProjectRoots(ctx context.Context, projectPaths []string, markers []string) []string {
+	if len(markers) == 0 {
+		markers = project.DefaultMarkers()
+	}
+
+	seen := map[string]bool{}
+	for _, projectPath := range projectPaths {
+		if absPath, err := filepath.Abs(projectPath); err == nil {
+			seen[strings.ToLower(absPath)] = true
+		}
+	}
+
+	roots := []string{}
+	for _, projectPath := range projectPaths {
+		path := process.ParseLocation(projectPath).Path
+		if info, err := pathutil.Probe(ctx, path); err != nil || info.IsDir() {
+			continue
+		}
+
+		root, ok := project.FindRoot(ctx, path, markers)
+		if !ok || seen[strings.ToLower(root)] {
+			continue
+		}
+
+		utils.Debug("Opening %s with its project root %s\n", projectPath, root)
+		seen[strings.ToLower(root)] = true
+		roots = append(roots, root)
+	}
+
+	return append(roots, projectPaths...)
+}
+
+// wslDistro picks the WSL distro for Linux paths: the one we're called from, else the configured one
+func wslDistro(cfg *config.Config) string {
+	if distro := os.Getenv("WSL_DISTRO_NAME"); distro != "" {
+		return distro
+	}
+
+	return cfg.WSLDistro
+}
+
+// launchError turns the result of process.LaunchZed into the CLI's exit code.
+// With --wait, Zed's own exit code is handed back to whoever is waiting on us (git, kubectl, ...).
+func launchError(err error) error {
+	var exitErr *process.ExitError
+	if errors.As(err, &exitErr) {
+		return cli.Exit("", exitErr.Code)
+	}
+
+	var cliErr *clierr.Error
+	if err != nil && !errors.As(err, &cliErr) {
+		utils.Error(err.Error())
+		return clierr.Wrap(clierr.General, err)
+	}
+
+	return err
+}
+
+// splitPassthroughArgs splits the command line at the first `--`.
+// Everything after it is meant for zed.exe and is kept away from our own flag parsing.
+func splitPassthroughArgs(args []string) ([]string, []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i], args[i+1:]
+		}
+	}
+
+	return args, nil
+}
+
+// windowModes maps the --new, --add and --reuse flags (and the window setting of workspace files) to their window mode
+var windowModes = map[string]process.WindowMode{
+	"new":   process.WindowNew,
+	"add":   process.WindowAdd,
+	"reuse": process.WindowReuse,
+}
+
+// windowModeFromFlags picks the window mode from the --new, --add and --reuse flags, which can't be combined
+func windowModeFromFlags(cmd *cli.Command) (process.WindowMode, error) {
+	selected := []string{}
+	windowMode := process.WindowDefault
+
+	for _, name := range []string{"new", "add", "reuse"} {
+		if cmd.Bool(name) {
+			selected = append(selected, "--"+name)
+			windowMode = windowModes[name]
+		}
+	}
+
+	if len(selected) > 1 {
+		return process.WindowDefault, fmt.Errorf("%s cannot be used together", strings.Join(selected, " and "))
+	}
+
+	return windowMode, nil
+}
+
+// createPolicyFromFlags picks the create policy, --create/--no-create override the configured one
+func createPolicyFromFlags(cmd *cli.Command, cfg *config.Config) (process.CreatePolicy, error) {
+	if cmd.IsSet("create") {
+		if cmd.Bool("create") {
+			return process.CreateAlways, nil
+		}
+
+		return process.CreateNever, nil
+	}
+
+	return process.ParseCreatePolicy(cfg.CreatePolicy)
+}
+
+// launchEnv collects the environment for Zed: env files first, then --env flags.
+// The --env-from-script script goes in LaunchOptions.EnvScript instead, so a dry run can leave it unrun.
+// Without --env-file the .env of the project being opened is picked up, but only when asked for: a cloned
+// repository's .env shouldn't quietly change the environment of the editor and its language servers.
+func launchEnv(ctx context.Context, cmd *cli.Command, cfg *config.Config, projectPaths []string) ([]string, error) {
+	env := []string{}
+
+	envFiles := cmd.StringSlice("env-file")
+	if len(envFiles) == 0 && len(projectPaths) > 0 && loadProjectEnv(cmd, cfg) {
+		projectEnv := filepath.Join(projectDir(ctx, projectPaths[0]), ".env")
+		if config.FileExists(projectEnv) {
+			envFiles = []string{projectEnv}
+		}
+	}
+
+	for _, envFile := range envFiles {
+		fileEnv, err := environ.ParseFile(envFile)
+		if err != nil {
+			return nil, err
+		}
+
+		utils.Debug("Loaded %d variables from %s\n", len(fileEnv), envFile)
+		env = append(env, fileEnv...)
+	}
+
+	for _, assignment := range cmd.StringSlice("env") {
+		key, value, err := environ.ParseAssignment(assignment)
+		if err != nil {
+			return nil, err
+		}
+
+		// `--env " GOFLAGS=-v"` sets GOFLAGS, not a variable whose name starts with a space.
+		env = append(env, key+"="+value)
+	}
+
+	return env, nil
+}
+
+// loadProjectEnv decides whether the project's .env and the env and zedArgs of workspace files are used,
+// --project-env/--no-project-env override the config
+func loadProjectEnv(cmd *cli.Command, cfg *config.Config) bool {
+	if cmd.IsSet("project-env") {
+		return cmd.Bool("project-env")
+	}
+
+	return cfg.LoadProjectEnv
+}
+
+// projectDir returns the folder a launch path belongs to: the path itself for folders, the parent for files
+func projectDir(ctx context.Context, projectPath string) string {
+	path := process.ParseLocation(projectPath).Path
+	if info, err := pathutil.Probe(ctx, path); err == nil && info.IsDir() {
+		return path
+	}
+
+	return filepath.Dir(path)
+}
+
+// resolveStdinArgs replaces `-` with a temp file holding the piped stdin content.
+// Piped input with no paths at all is treated the same as `zed -`. A dry run keeps the `-` and leaves stdin unread.
+func resolveStdinArgs(args []string, dryRun bool) ([]string, error) {
+	if len(args) == 0 && process.StdinIsPiped() {
+		args = []string{process.StdinArg}
+	}
+
+	resolved := make([]string, 0, len(args))
+	captured := false
+
+	for _, arg := range args {
+		if arg != process.StdinArg {
+			resolved = append(resolved, arg)
+			continue
+		}
+
+		if captured {
+			return nil, fmt.Errorf("stdin can only be opened once")
+		}
+
+		if dryRun {
+			captured = true
+			resolved = append(resolved, arg)
+			continue
+		}
+
+		tempPath, err := process.CaptureStdin(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		captured = true
+		resolved = append(resolved, tempPath)
 	}
 
-	return app.Run(ctx, os.Args)
+	return resolved, nil
 }
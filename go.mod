@@ -3,10 +3,7 @@ module zed-cli-win-unofficial
 go 1.24.1
 
 require (
-	github.com/bi-zone/go-fileversion v1.0.0
 	github.com/hashicorp/go-version v1.7.0
 	github.com/urfave/cli/v3 v3.3.3
 	golang.org/x/sys v0.33.0
 )
-
-require golang.org/x/xerrors v0.0.0-20190717185122-a985d3407aa7 // indirect
@@ -0,0 +1,57 @@
+package clierr
+
+import "fmt"
+
+// Code is the documented exit code of the CLI process
+type Code int
+
+const (
+	// Success means the command did what it was asked to
+	Success Code = 0
+	// General is any failure without a more specific code
+	General Code = 1
+	// Usage means the flags or arguments were invalid
+	Usage Code = 2
+	// ConfigMissing means the config file doesn't exist yet (run `zed config set`)
+	ConfigMissing Code = 3
+	// ZedNotFound means the configured Zed executable doesn't exist or can't be started
+	ZedNotFound Code = 4
+	// VersionTooOld means the installed Zed version doesn't support what was asked for
+	VersionTooOld Code = 5
+	// RegistryFailure means a registry entry couldn't be written or removed
+	RegistryFailure Code = 6
+	// InvalidPath means a given path doesn't exist or can't be opened
+	InvalidPath Code = 7
+	// ConfigInvalid means the config file couldn't be read, parsed or saved
+	ConfigInvalid Code = 8
+)
+
+// Error is an error that carries the exit code of the CLI process.
+// The friendly message has usually been printed already, so the error text is meant for logs and callers.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode implements the cli.ExitCoder interface of urfave/cli
+func (e *Error) ExitCode() int {
+	return int(e.Code)
+}
+
+// New creates an error with the given exit code and a formatted message
+func New(code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap attaches an exit code to an existing error
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
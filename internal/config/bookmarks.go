@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BookmarkPrefix marks a launch target as a bookmark name, eg: `zed @platform`
+const BookmarkPrefix string = "@"
+
+// BookmarkName strips the optional BookmarkPrefix from a bookmark name given on the command line
+func BookmarkName(name string) string {
+	return strings.TrimPrefix(strings.TrimSpace(name), BookmarkPrefix)
+}
+
+// FindBookmark looks up a bookmark by name, ignoring case, and returns its stored name and paths
+func (c *Config) FindBookmark(name string) (string, []string, bool) {
+	name = BookmarkName(name)
+	for stored, paths := range c.Bookmarks {
+		if strings.EqualFold(stored, name) {
+			return stored, paths, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// AddBookmark stores the paths under the name, adding them to the paths the bookmark already has
+func (c *Config) AddBookmark(name string, paths []string) error {
+	name = BookmarkName(name)
+	if err := validateBookmarkName(name); err != nil {
+		return err
+	}
+
+	stored, existing, ok := c.FindBookmark(name)
+	if !ok {
+		stored = name
+	}
+
+	for _, path := range paths {
+		if !slices.ContainsFunc(existing, func(p string) bool { return strings.EqualFold(p, path) }) {
+			existing = append(existing, path)
+		}
+	}
+
+	if c.Bookmarks == nil {
+		c.Bookmarks = map[string][]string{}
+	}
+
+	c.Bookmarks[stored] = existing
+	return nil
+}
+
+// RemoveBookmark removes a bookmark with all its paths
+func (c *Config) RemoveBookmark(name string) error {
+	stored, _, ok := c.FindBookmark(name)
+	if !ok {
+		return fmt.Errorf("no bookmark named '%s'", BookmarkName(name))
+	}
+
+	delete(c.Bookmarks, stored)
+	return nil
+}
+
+// RenameBookmark gives a bookmark a new name, the new name must not be taken by another bookmark
+func (c *Config) RenameBookmark(oldName string, newName string) error {
+	newName = BookmarkName(newName)
+	if err := validateBookmarkName(newName); err != nil {
+		return err
+	}
+
+	stored, paths, ok := c.FindBookmark(oldName)
+	if !ok {
+		return fmt.Errorf("no bookmark named '%s'", BookmarkName(oldName))
+	}
+
+	if other, _, taken := c.FindBookmark(newName); taken && other != stored {
+		return fmt.Errorf("a bookmark named '%s' already exists", other)
+	}
+
+	delete(c.Bookmarks, stored)
+	c.Bookmarks[newName] = paths
+	return nil
+}
+
+// validateBookmarkName checks that a name can be typed as `@name` on the command line
+func validateBookmarkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("bookmark name cannot be empty")
+	}
+
+	if strings.ContainsAny(name, " \t\\/:*?\"<>|@") {
+		return fmt.Errorf("bookmark name '%s' cannot contain spaces, path separators or @", name)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,14 +10,45 @@ import (
 )
 
 type Config struct {
-	ZedPath            string `json:"zedPath"`
-	ContextMenuEnabled bool   `json:"contextMenuEnabled"`
+	ZedPath            string              `json:"zedPath"`
+	ContextMenuEnabled bool                `json:"contextMenuEnabled"`
+	ZedArgs            []string            `json:"zedArgs,omitempty"`
+	CreatePolicy       string              `json:"createPolicy,omitempty"`
+	ZedVersion         string              `json:"zedVersion,omitempty"`
+	Installs           []Install           `json:"installs,omitempty"`
+	DefaultInstall     string              `json:"defaultInstall,omitempty"`
+	Bookmarks          map[string][]string `json:"bookmarks,omitempty"`
+	OpenProjectRoot    bool                `json:"openProjectRoot,omitempty"`
+	RootMarkers        []string            `json:"rootMarkers,omitempty"`
+	WSLDistro          string              `json:"wslDistro,omitempty"`
+	PathTimeoutSeconds int                 `json:"pathTimeoutSeconds,omitempty"`
+	LoadProjectEnv     bool                `json:"loadProjectEnv,omitempty"`
+}
+
+// ConfigDir returns the directory holding the configuration file and the CLI's other data.
+func ConfigDir() string {
+	appData := os.Getenv("APPDATA")
+	return filepath.Join(appData, "zed-cli-win-unofficial")
 }
 
 // ConfigPath returns the path of the configuration file.
 func ConfigPath() string {
-	appData := os.Getenv("APPDATA")
-	return filepath.Join(appData, "zed-cli-win-unofficial", "config.json")
+	return filepath.Join(ConfigDir(), "config.json")
+}
+
+// LogPath returns the path of the log file that captures Zed's output.
+func LogPath() string {
+	return filepath.Join(ConfigDir(), "logs", "zed.log")
+}
+
+// HistoryPath returns the path of the file holding the recently opened projects.
+func HistoryPath() string {
+	return filepath.Join(ConfigDir(), "history.json")
+}
+
+// VersionCachePath returns the path of the file caching the detected Zed versions.
+func VersionCachePath() string {
+	return filepath.Join(ConfigDir(), "version-cache.json")
 }
 
 // SaveConfig saves the configuration to disk (config.json)
@@ -28,17 +60,27 @@ func SaveConfig(config *Config) error {
 		return fmt.Errorf("unable to create config directory: %w", err)
 	}
 
-	file, err := os.Create(configPath)
+	data, err := json.MarshalIndent(config, "", " ")
+	if err != nil {
+		return fmt.Errorf("unable to save config data: %w", err)
+	}
+
+	// Written next to the config and renamed over it, so a failed write can't leave half a config behind.
+	tempFile, err := os.CreateTemp(configDir, filepath.Base(configPath)+".*.tmp")
 	if err != nil {
 		return fmt.Errorf("unable to create config file: %w", err)
 	}
 
-	defer file.Close()
+	_, writeErr := tempFile.Write(append(data, '\n'))
+	closeErr := tempFile.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", " ")
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tempFile.Name())
+		return fmt.Errorf("unable to save config data: %w", err)
+	}
 
-	if err := encoder.Encode(config); err != nil {
+	if err := os.Rename(tempFile.Name(), configPath); err != nil {
+		os.Remove(tempFile.Name())
 		return fmt.Errorf("unable to save config data: %w", err)
 	}
 
@@ -62,7 +104,7 @@ func LoadConfig() (*Config, error) {
 	defer file.Close()
 
 	var config Config
-	if err := json.NewDecoder(file).Decode(&config); os.IsNotExist(err) {
+	if err := json.NewDecoder(file).Decode(&config); err != nil {
 		return nil, fmt.Errorf("unable to read config file: %w", err)
 	}
 
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveConfigRoundTrip(t *testing.T) {
+	t.Setenv("APPDATA", t.TempDir())
+
+	if _, err := LoadConfig(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("LoadConfig() without a config = %v, want %v", err, os.ErrNotExist)
+	}
+
+	cfg := &Config{ZedPath: `C:\Zed\zed.exe`, Bookmarks: map[string][]string{"api": {`C:\code\api`}}}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() = %v", err)
+	}
+
+	loaded, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() = %v", err)
+	}
+
+	if loaded.ZedPath != cfg.ZedPath || len(loaded.Bookmarks["api"]) != 1 {
+		t.Errorf("LoadConfig() = %+v, want %+v", loaded, cfg)
+	}
+
+	// Nothing but the config itself is left in the folder.
+	entries, err := os.ReadDir(ConfigDir())
+	if err != nil || len(entries) != 1 || entries[0].Name() != "config.json" {
+		t.Errorf("config folder holds %v, %v; want just config.json", entries, err)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	t.Setenv("APPDATA", t.TempDir())
+
+	if err := os.MkdirAll(ConfigDir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(ConfigDir(), "config.json"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Callers only start over with an empty config when the file is missing, never when it's broken.
+	if _, err := LoadConfig(); err == nil || errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadConfig() = %v, want a read error", err)
+	}
+}
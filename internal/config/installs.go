@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Known release channels of Zed
+const (
+	ChannelStable  string = "stable"
+	ChannelPreview string = "preview"
+	ChannelNightly string = "nightly"
+	ChannelDev     string = "dev"
+)
+
+// Channels lists the release channels an install can belong to
+func Channels() []string {
+	return []string{ChannelStable, ChannelPreview, ChannelNightly, ChannelDev}
+}
+
+// Install is a named Zed installation the CLI can launch
+type Install struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Channel string `json:"channel"`
+	Version string `json:"version,omitempty"`
+}
+
+// ParseChannel validates a channel name, an empty value means ChannelStable
+func ParseChannel(channel string) (string, error) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	if channel == "" {
+		return ChannelStable, nil
+	}
+
+	for _, known := range Channels() {
+		if channel == known {
+			return channel, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown channel '%s' (expected %s)", channel, strings.Join(Channels(), ", "))
+}
+
+// GuessChannel guesses the release channel from the install path, eg: `...\Zed Preview\zed.exe`
+func GuessChannel(path string) string {
+	lower := strings.ToLower(path)
+
+	switch {
+	case strings.Contains(lower, "preview"):
+		return ChannelPreview
+	case strings.Contains(lower, "nightly"):
+		return ChannelNightly
+	case strings.Contains(lower, `target\debug`), strings.Contains(lower, `target\release`), strings.Contains(lower, "target/"):
+		return ChannelDev
+	default:
+		return ChannelStable
+	}
+}
+
+// FindInstall looks up an install by name, ignoring case
+func (c *Config) FindInstall(name string) (*Install, bool) {
+	for i := range c.Installs {
+		if strings.EqualFold(c.Installs[i].Name, name) {
+			return &c.Installs[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// AddInstall registers a new install, names must be unique
+func (c *Config) AddInstall(install Install) error {
+	if strings.TrimSpace(install.Name) == "" {
+		return fmt.Errorf("install name cannot be empty")
+	}
+
+	if _, exists := c.FindInstall(install.Name); exists {
+		return fmt.Errorf("an install named '%s' already exists", install.Name)
+	}
+
+	c.Installs = append(c.Installs, install)
+	return nil
+}
+
+// RemoveInstall removes an install, forgetting it as the default when it was one
+func (c *Config) RemoveInstall(name string) error {
+	for i, install := range c.Installs {
+		if !strings.EqualFold(install.Name, name) {
+			continue
+		}
+
+		c.Installs = append(c.Installs[:i], c.Installs[i+1:]...)
+		if strings.EqualFold(c.DefaultInstall, name) {
+			c.DefaultInstall = ""
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no install named '%s'", name)
+}
+
+// SetDefaultInstall makes the named install the one plain `zed` launches
+func (c *Config) SetDefaultInstall(name string) error {
+	install, ok := c.FindInstall(name)
+	if !ok {
+		return fmt.Errorf("no install named '%s'", name)
+	}
+
+	c.DefaultInstall = install.Name
+	c.ZedPath = install.Path
+	return nil
+}
+
+// ResolveZedPath picks the Zed executable to launch: the named install, else the first install of the channel
+// (preferring the default one), else the configured ZedPath.
+func (c *Config) ResolveZedPath(installName string, channel string) (string, error) {
+	if installName != "" {
+		install, ok := c.FindInstall(installName)
+		if !ok {
+			return "", fmt.Errorf("no install named '%s'", installName)
+		}
+
+		return install.Path, nil
+	}
+
+	if channel == "" {
+		return c.ZedPath, nil
+	}
+
+	channel, err := ParseChannel(channel)
+	if err != nil {
+		return "", err
+	}
+
+	if install, ok := c.FindInstall(c.DefaultInstall); ok && install.Channel == channel {
+		return install.Path, nil
+	}
+
+	for _, install := range c.Installs {
+		if install.Channel == channel {
+			return install.Path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no %s install configured", channel)
+}
@@ -5,13 +5,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"zed-cli-win-unofficial/internal/pathutil"
 	"zed-cli-win-unofficial/internal/utils"
 )
 
-// FileExists: Checks if the given path to a file exists or not
+// FileExists: Checks if the given path to a file exists or not, long and UNC paths included
 func FileExists(path string) bool {
-	_, err := os.Stat(path)
-	return err == nil
+	return pathutil.Exists(path)
 }
 
 // resolvePath: resolves the given path to normal, if there's ENV present else just return the normal path
@@ -32,7 +32,7 @@ func resolvePath(path string) (string, error) {
 	restOfPath := path[end+1:]
 	resolvedPath := filepath.Join(envValue, restOfPath)
 	utils.Debug("Using resolved path: %s\n", resolvedPath)
-	return resolvedPath, nil
+	return pathutil.Clean(resolvedPath), nil
 }
 
 // ValidatePath: validates that a path exists and resolves environment variables
@@ -47,5 +47,5 @@ func ValidatePath(path string) (string, error) {
 		return "", fmt.Errorf("file not found at path: %s", resolvedPath)
 	}
 
-	return resolvedPath, nil
+	return pathutil.Clean(resolvedPath), nil
 }
@@ -0,0 +1,274 @@
+package discovery
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// zedExecutable is the file name of the Zed editor on Windows
+const zedExecutable string = "zed.exe"
+
+// FS is the part of the filesystem discovery reads, so it can be swapped for a fake one
+type FS interface {
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	ReadFile(name string) ([]byte, error)
+}
+
+// OSFS is the FS backed by the real filesystem
+type OSFS struct{}
+
+func (OSFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (OSFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (OSFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+
+// Candidate is a Zed executable found on the machine
+type Candidate struct {
+	// Path is the full path of zed.exe
+	Path string
+	// Source names where it was found, eg: "Scoop" or "PATH"
+	Source string
+}
+
+// Finder searches the well-known install locations of Zed
+type Finder struct {
+	// FS is the filesystem that is searched
+	FS FS
+	// Getenv looks up environment variables such as LOCALAPPDATA and PATH
+	Getenv func(key string) string
+	// Exclude is skipped when found, eg: the CLI's own executable
+	Exclude string
+}
+
+// NewFinder creates a Finder for the real filesystem and environment
+func NewFinder() *Finder {
+	self, _ := os.Executable()
+	return &Finder{FS: OSFS{}, Getenv: os.Getenv, Exclude: self}
+}
+
+// Find returns every Zed executable found, in order of how likely it's the one the user wants.
+// The same executable found through several locations is only listed once.
+func (f *Finder) Find() []Candidate {
+	searches := []struct {
+		source string
+		find   func() []string
+	}{
+		{source: "Zed installer", find: f.installerPaths},
+		{source: "Scoop", find: f.scoopPaths},
+		{source: "winget", find: f.wingetPaths},
+		{source: "Chocolatey", find: f.chocolateyPaths},
+		{source: "PATH", find: f.pathEntries},
+		{source: "Start Menu", find: f.startMenuShortcuts},
+	}
+
+	candidates := []Candidate{}
+	seen := map[string]bool{}
+
+	for _, search := range searches {
+		for _, path := range search.find() {
+			key := strings.ToLower(filepath.Clean(path))
+			if seen[key] || !f.isExecutable(path) || (f.Exclude != "" && strings.EqualFold(filepath.Clean(f.Exclude), filepath.Clean(path))) {
+				continue
+			}
+
+			seen[key] = true
+			candidates = append(candidates, Candidate{Path: path, Source: search.source})
+		}
+	}
+
+	return candidates
+}
+
+// isExecutable checks if the path is an existing file
+func (f *Finder) isExecutable(path string) bool {
+	info, err := f.FS.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// installerPaths are where the official installers put Zed
+func (f *Finder) installerPaths() []string {
+	paths := []string{}
+
+	if localAppData := f.Getenv("LOCALAPPDATA"); localAppData != "" {
+		paths = append(paths,
+			filepath.Join(localAppData, "Programs", "Zed", zedExecutable),
+			filepath.Join(localAppData, "Programs", "Zed Preview", zedExecutable),
+		)
+	}
+
+	if programFiles := f.Getenv("ProgramFiles"); programFiles != "" {
+		paths = append(paths,
+			filepath.Join(programFiles, "Zed", zedExecutable),
+			filepath.Join(programFiles, "Zed Preview", zedExecutable),
+		)
+	}
+
+	return paths
+}
+
+// scoopPaths looks at `apps\zed*\current` of the user and global Scoop roots
+func (f *Finder) scoopPaths() []string {
+	roots := []string{f.Getenv("SCOOP"), f.Getenv("SCOOP_GLOBAL")}
+	if userProfile := f.Getenv("USERPROFILE"); userProfile != "" {
+		roots = append(roots, filepath.Join(userProfile, "scoop"))
+	}
+
+	if programData := f.Getenv("ProgramData"); programData != "" {
+		roots = append(roots, filepath.Join(programData, "scoop"))
+	}
+
+	paths := []string{}
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+
+		for _, app := range f.zedDirs(filepath.Join(root, "apps"), "zed") {
+			paths = append(paths, filepath.Join(app, "current", zedExecutable))
+		}
+	}
+
+	return paths
+}
+
+// wingetPaths looks at the portable packages winget unpacks under LOCALAPPDATA
+func (f *Finder) wingetPaths() []string {
+	localAppData := f.Getenv("LOCALAPPDATA")
+	if localAppData == "" {
+		return nil
+	}
+
+	paths := []string{}
+	for _, pkg := range f.zedDirs(filepath.Join(localAppData, "Microsoft", "WinGet", "Packages"), "zedindustries.zed") {
+		paths = append(paths, f.withSubdirs(pkg)...)
+	}
+
+	return paths
+}
+
+// chocolateyPaths looks at `lib\zed*\tools` of the Chocolatey root
+func (f *Finder) chocolateyPaths() []string {
+	root := f.Getenv("ChocolateyInstall")
+	if root == "" {
+		if programData := f.Getenv("ProgramData"); programData != "" {
+			root = filepath.Join(programData, "chocolatey")
+		}
+	}
+
+	if root == "" {
+		return nil
+	}
+
+	paths := []string{}
+	for _, pkg := range f.zedDirs(filepath.Join(root, "lib"), "zed") {
+		paths = append(paths, f.withSubdirs(filepath.Join(pkg, "tools"))...)
+	}
+
+	return paths
+}
+
+// pathEntries looks for zed.exe in every PATH directory.
+// The official installer puts a small CLI in `Zed\bin`, in which case only the editor next to it is used.
+func (f *Finder) pathEntries() []string {
+	paths := []string{}
+
+	for _, dir := range filepath.SplitList(f.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+
+		if strings.EqualFold(filepath.Base(dir), "bin") {
+			if editor := filepath.Join(filepath.Dir(dir), zedExecutable); f.isExecutable(editor) {
+				paths = append(paths, editor)
+				continue
+			}
+		}
+
+		paths = append(paths, filepath.Join(dir, zedExecutable))
+	}
+
+	return paths
+}
+
+// startMenuShortcuts resolves the Zed shortcuts in the user and common Start Menu
+func (f *Finder) startMenuShortcuts() []string {
+	roots := []string{}
+	if appData := f.Getenv("APPDATA"); appData != "" {
+		roots = append(roots, filepath.Join(appData, "Microsoft", "Windows", "Start Menu", "Programs"))
+	}
+
+	if programData := f.Getenv("ProgramData"); programData != "" {
+		roots = append(roots, filepath.Join(programData, "Microsoft", "Windows", "Start Menu", "Programs"))
+	}
+
+	paths := []string{}
+	for _, root := range roots {
+		// Shortcuts sit either directly in Programs or in a `Zed` folder inside it.
+		dirs := append([]string{root}, f.zedDirs(root, "zed")...)
+
+		for _, dir := range dirs {
+			entries, err := f.FS.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+
+			for _, entry := range entries {
+				name := strings.ToLower(entry.Name())
+				if entry.IsDir() || !strings.HasPrefix(name, "zed") || filepath.Ext(name) != ".lnk" {
+					continue
+				}
+
+				data, err := f.FS.ReadFile(filepath.Join(dir, entry.Name()))
+				if err != nil {
+					continue
+				}
+
+				if target, err := parseShortcutTarget(data); err == nil && strings.EqualFold(filepath.Base(target), zedExecutable) {
+					paths = append(paths, target)
+				}
+			}
+		}
+	}
+
+	return paths
+}
+
+// zedDirs lists the sub directories of dir whose name starts with prefix, leaving out this CLI's own packages
+func (f *Finder) zedDirs(dir string, prefix string) []string {
+	entries, err := f.FS.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	dirs := []string{}
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if !entry.IsDir() || !strings.HasPrefix(name, prefix) || strings.Contains(name, "cli") {
+			continue
+		}
+
+		dirs = append(dirs, filepath.Join(dir, entry.Name()))
+	}
+
+	return dirs
+}
+
+// withSubdirs returns zed.exe in dir and in each of its direct sub directories
+func (f *Finder) withSubdirs(dir string) []string {
+	paths := []string{filepath.Join(dir, zedExecutable)}
+
+	entries, err := f.FS.ReadDir(dir)
+	if err != nil {
+		return paths
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			paths = append(paths, filepath.Join(dir, entry.Name(), zedExecutable))
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,84 @@
+package discovery
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// mapFS serves rooted paths such as /local/Programs/Zed/zed.exe from an in-memory filesystem
+type mapFS fstest.MapFS
+
+func (m mapFS) key(name string) string {
+	return strings.TrimPrefix(filepath.ToSlash(name), "/")
+}
+
+func (m mapFS) Stat(name string) (fs.FileInfo, error) { return fs.Stat(fstest.MapFS(m), m.key(name)) }
+func (m mapFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(fstest.MapFS(m), m.key(name))
+}
+func (m mapFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(fstest.MapFS(m), m.key(name))
+}
+
+// rooted turns a slash path into a rooted path for the current OS
+func rooted(path string) string {
+	return filepath.FromSlash("/" + path)
+}
+
+func TestFinderFind(t *testing.T) {
+	files := mapFS{
+		"local/Programs/Zed/bin/zed.exe":                                 {},
+		"local/Programs/Zed/zed.exe":                                     {},
+		"scoop/apps/zed/current/zed.exe":                                 {},
+		"scoop/apps/zed-cli-win-unofficial/current/zed.exe":              {},
+		"local/Microsoft/WinGet/Packages/ZedIndustries.Zed_x/zed.exe":    {},
+		"local/Microsoft/WinGet/Packages/ZedIndustries.Zed_x/v1/zed.exe": {},
+		"choco/lib/zed/tools/zed.exe":                                    {},
+		"tools/zed.exe":                                                  {},
+		"appdata/Microsoft/Windows/Start Menu/Programs/Zed/Zed.lnk":      {Data: shortcut(rooted("lnk/"), "zed.exe")},
+		"shim/bin/zed.exe":                                               {},
+		"lnk/zed.exe":                                                    {},
+	}
+
+	env := map[string]string{
+		"LOCALAPPDATA":      rooted("local"),
+		"SCOOP":             rooted("scoop"),
+		"ChocolateyInstall": rooted("choco"),
+		"APPDATA":           rooted("appdata"),
+		"PATH":              strings.Join([]string{rooted("local/Programs/Zed/bin"), rooted("tools"), rooted("missing"), rooted("shim/bin")}, string(filepath.ListSeparator)),
+	}
+
+	finder := &Finder{FS: files, Getenv: func(key string) string { return env[key] }, Exclude: rooted("tools/zed.exe")}
+
+	want := []Candidate{
+		{Path: rooted("local/Programs/Zed/zed.exe"), Source: "Zed installer"},
+		{Path: rooted("scoop/apps/zed/current/zed.exe"), Source: "Scoop"},
+		{Path: rooted("local/Microsoft/WinGet/Packages/ZedIndustries.Zed_x/zed.exe"), Source: "winget"},
+		{Path: rooted("local/Microsoft/WinGet/Packages/ZedIndustries.Zed_x/v1/zed.exe"), Source: "winget"},
+		{Path: rooted("choco/lib/zed/tools/zed.exe"), Source: "Chocolatey"},
+		{Path: rooted("shim/bin/zed.exe"), Source: "PATH"},
+		{Path: rooted("lnk/zed.exe"), Source: "Start Menu"},
+	}
+
+	got := finder.Find()
+	if len(got) != len(want) {
+		t.Fatalf("Find() = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Find()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFinderFindNothing(t *testing.T) {
+	finder := &Finder{FS: mapFS{}, Getenv: func(string) string { return "" }}
+
+	if got := finder.Find(); len(got) != 0 {
+		t.Errorf("Find() = %v, want no candidates", got)
+	}
+}
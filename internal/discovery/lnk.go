@@ -0,0 +1,107 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+const (
+	// shellLinkHeaderSize is the fixed size of the header every .lnk file starts with
+	shellLinkHeaderSize = 0x4C
+	// hasLinkTargetIDList and hasLinkInfo are the LinkFlags bits for the optional sections we need to walk
+	hasLinkTargetIDList = 0x1
+	hasLinkInfo         = 0x2
+	// linkInfoHeaderSize is the smallest LinkInfo header, the one without the Unicode offsets
+	linkInfoHeaderSize = 0x1C
+	// volumeIDAndLocalBasePath is the LinkInfoFlags bit set when the target lives on a local volume
+	volumeIDAndLocalBasePath = 0x1
+)
+
+// parseShortcutTarget reads the local target path out of a Windows shortcut (.lnk) file.
+// Only the LinkInfo section is used, which is where shortcuts made by installers keep the full path.
+func parseShortcutTarget(data []byte) (string, error) {
+	if len(data) < shellLinkHeaderSize || binary.LittleEndian.Uint32(data) != shellLinkHeaderSize {
+		return "", fmt.Errorf("not a shortcut file")
+	}
+
+	flags := binary.LittleEndian.Uint32(data[0x14:])
+	pos := shellLinkHeaderSize
+
+	if flags&hasLinkTargetIDList != 0 {
+		if pos+2 > len(data) {
+			return "", fmt.Errorf("truncated shortcut file")
+		}
+
+		pos += 2 + int(binary.LittleEndian.Uint16(data[pos:]))
+	}
+
+	if flags&hasLinkInfo == 0 || pos+linkInfoHeaderSize > len(data) {
+		return "", fmt.Errorf("shortcut has no target path")
+	}
+
+	info := data[pos:]
+	size := int(binary.LittleEndian.Uint32(info))
+	if size > len(info) {
+		return "", fmt.Errorf("truncated shortcut file")
+	}
+
+	if size < linkInfoHeaderSize {
+		return "", fmt.Errorf("invalid shortcut file")
+	}
+
+	info = info[:size]
+	headerSize := binary.LittleEndian.Uint32(info[4:])
+	infoFlags := binary.LittleEndian.Uint32(info[8:])
+
+	if infoFlags&volumeIDAndLocalBasePath == 0 {
+		return "", fmt.Errorf("shortcut doesn't point at a local path")
+	}
+
+	// Newer shortcuts carry Unicode copies of the paths, which survive non-ASCII folder names.
+	if headerSize >= 0x24 && len(info) >= 0x24 {
+		basePath := utf16String(info, binary.LittleEndian.Uint32(info[28:]))
+		suffix := utf16String(info, binary.LittleEndian.Uint32(info[32:]))
+		if basePath != "" {
+			return basePath + suffix, nil
+		}
+	}
+
+	basePath := cString(info, binary.LittleEndian.Uint32(info[16:]))
+	suffix := cString(info, binary.LittleEndian.Uint32(info[24:]))
+	if basePath == "" {
+		return "", fmt.Errorf("shortcut has no target path")
+	}
+
+	return basePath + suffix, nil
+}
+
+// cString reads a NUL terminated ANSI string starting at offset
+func cString(data []byte, offset uint32) string {
+	if offset == 0 || int(offset) >= len(data) {
+		return ""
+	}
+
+	value, _, _ := bytes.Cut(data[offset:], []byte{0})
+	return string(value)
+}
+
+// utf16String reads a NUL terminated UTF-16LE string starting at offset
+func utf16String(data []byte, offset uint32) string {
+	if offset == 0 || int(offset) >= len(data) {
+		return ""
+	}
+
+	chars := []uint16{}
+	for i := int(offset); i+1 < len(data); i += 2 {
+		char := binary.LittleEndian.Uint16(data[i:])
+		if char == 0 {
+			break
+		}
+
+		chars = append(chars, char)
+	}
+
+	return string(utf16.Decode(chars))
+}
@@ -0,0 +1,73 @@
+package discovery
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// shortcut builds a minimal .lnk file whose LinkInfo points at basePath + suffix
+func shortcut(basePath string, suffix string) []byte {
+	data := make([]byte, shellLinkHeaderSize)
+	binary.LittleEndian.PutUint32(data, shellLinkHeaderSize)
+	binary.LittleEndian.PutUint32(data[0x14:], hasLinkInfo)
+
+	strs := append(append([]byte(basePath), 0), append([]byte(suffix), 0)...)
+	info := make([]byte, linkInfoHeaderSize, linkInfoHeaderSize+len(strs))
+	binary.LittleEndian.PutUint32(info, uint32(linkInfoHeaderSize+len(strs)))
+	binary.LittleEndian.PutUint32(info[4:], linkInfoHeaderSize)
+	binary.LittleEndian.PutUint32(info[8:], volumeIDAndLocalBasePath)
+	binary.LittleEndian.PutUint32(info[16:], linkInfoHeaderSize)
+	binary.LittleEndian.PutUint32(info[24:], uint32(linkInfoHeaderSize+len(basePath)+1))
+
+	return append(data, append(info, strs...)...)
+}
+
+func TestParseShortcutTarget(t *testing.T) {
+	target, err := parseShortcutTarget(shortcut(`C:\Users\me\AppData\Local\Programs\Zed\`, "zed.exe"))
+	if err != nil {
+		t.Fatalf("parseShortcutTarget: %v", err)
+	}
+
+	if want := `C:\Users\me\AppData\Local\Programs\Zed\zed.exe`; target != want {
+		t.Errorf("target = %q, want %q", target, want)
+	}
+}
+
+func TestParseShortcutTargetMalformed(t *testing.T) {
+	withInfoSize := func(size uint32) []byte {
+		data := shortcut(`C:\Zed\zed.exe`, "")
+		binary.LittleEndian.PutUint32(data[shellLinkHeaderSize:], size)
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "header only", data: shortcut("", "")[:shellLinkHeaderSize]},
+		{name: "link info size 0", data: withInfoSize(0)},
+		{name: "link info size 8", data: withInfoSize(8)},
+		{name: "link info size 27", data: withInfoSize(27)},
+		{name: "link info size past the end", data: withInfoSize(1 << 20)},
+		{name: "no base path", data: shortcut("", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if target, err := parseShortcutTarget(tt.data); err == nil {
+				t.Errorf("parseShortcutTarget = %q, want an error", target)
+			}
+		})
+	}
+}
+
+func TestParseShortcutTargetOffsetsOutOfRange(t *testing.T) {
+	data := shortcut(`C:\Zed\zed.exe`, "")
+	binary.LittleEndian.PutUint32(data[shellLinkHeaderSize+16:], 0xFFFF)
+
+	if _, err := parseShortcutTarget(data); err == nil || !strings.Contains(err.Error(), "no target path") {
+		t.Errorf("err = %v, want a missing target path", err)
+	}
+}
@@ -0,0 +1,148 @@
+package environ
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ParseAssignment splits a `KEY=VALUE` assignment, the value may be empty but the key may not
+func ParseAssignment(assignment string) (string, string, error) {
+	key, value, found := strings.Cut(assignment, "=")
+	key = strings.TrimSpace(key)
+
+	if !found || key == "" {
+		return "", "", fmt.Errorf("invalid environment variable '%s', expected KEY=VALUE", assignment)
+	}
+
+	return key, value, nil
+}
+
+// ParseFile reads a .env style file into `KEY=VALUE` assignments.
+// Blank lines, `#` comments and an `export ` prefix are allowed, values may be single or double quoted.
+func ParseFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open env file: %w", err)
+	}
+	defer file.Close()
+
+	assignments := []string{}
+	scanner := bufio.NewScanner(file)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := ParseAssignment(strings.TrimPrefix(line, "export "))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+
+		assignments = append(assignments, key+"="+unquote(strings.TrimSpace(value)))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read env file: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// unquote strips matching quotes from a value, double quotes also support \n, \t, \" and \\ escapes
+func unquote(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+
+	switch {
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1]
+	case value[0] == '"' && value[len(value)-1] == '"':
+		replacer := strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`)
+		return replacer.Replace(value[1 : len(value)-1])
+	default:
+		return value
+	}
+}
+
+// FromScript runs a setup script (eg: VsDevCmd.bat) and returns the variables it added or changed
+func FromScript(script string) ([]string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		// `call` returns to our `set` after the batch file finishes, its own output is discarded.
+		cmd = exec.Command("cmd.exe", "/d", "/c", "call", script, ">nul", "2>&1", "&&", "set")
+	} else {
+		cmd = exec.Command("sh", "-c", `. "$0" >/dev/null 2>&1 && env`, script)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("env script %s failed: %w", script, err)
+	}
+
+	return Diff(os.Environ(), strings.Split(strings.ReplaceAll(string(output), "\r\n", "\n"), "\n")), nil
+}
+
+// Diff returns the assignments in after that are new or different from before
+func Diff(before []string, after []string) []string {
+	previous := map[string]string{}
+	for _, assignment := range before {
+		if key, value, err := ParseAssignment(assignment); err == nil {
+			previous[normalizeKey(key)] = value
+		}
+	}
+
+	changed := []string{}
+	for _, assignment := range after {
+		key, value, err := ParseAssignment(assignment)
+		if err != nil {
+			continue
+		}
+
+		if old, ok := previous[normalizeKey(key)]; !ok || old != value {
+			changed = append(changed, key+"="+value)
+		}
+	}
+
+	return changed
+}
+
+// Merge applies the overrides on top of the base environment, later overrides win
+func Merge(base []string, overrides []string) []string {
+	merged := make([]string, 0, len(base)+len(overrides))
+	index := map[string]int{}
+
+	for _, assignment := range append(append([]string{}, base...), overrides...) {
+		key, _, err := ParseAssignment(assignment)
+		if err != nil {
+			// Windows keeps a few `=C:=C:\path` entries, they are passed along untouched.
+			merged = append(merged, assignment)
+			continue
+		}
+
+		if i, ok := index[normalizeKey(key)]; ok {
+			merged[i] = assignment
+			continue
+		}
+
+		index[normalizeKey(key)] = len(merged)
+		merged = append(merged, assignment)
+	}
+
+	return merged
+}
+
+// normalizeKey makes variable names compare the way the OS does, Windows ignores case
+func normalizeKey(key string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(key)
+	}
+
+	return key
+}
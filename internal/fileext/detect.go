@@ -0,0 +1,108 @@
+package fileext
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// DefaultExtension is used when the content doesn't match any known format
+const DefaultExtension string = ".txt"
+
+// contentRule maps a recognizable start of a file to the extension it's saved with
+type contentRule struct {
+	prefixes  []string
+	extension string
+}
+
+var contentRules = []contentRule{
+	{prefixes: []string{"diff --git ", "--- ", "Index: ", "@@ "}, extension: ".diff"},
+	{prefixes: []string{"<?xml"}, extension: ".xml"},
+	{prefixes: []string{"<!doctype html", "<html"}, extension: ".html"},
+	{prefixes: []string{"<svg"}, extension: ".svg"},
+	{prefixes: []string{"---\n", "---\r\n", "apiVersion:"}, extension: ".yaml"},
+	{prefixes: []string{"package "}, extension: ".go"},
+	{prefixes: []string{"# ", "## "}, extension: ".md"},
+}
+
+// shebangRules maps an interpreter named in a `#!` line to its script extension
+var shebangRules = []contentRule{
+	{prefixes: []string{"python"}, extension: ".py"},
+	{prefixes: []string{"node"}, extension: ".js"},
+	{prefixes: []string{"ruby"}, extension: ".rb"},
+	{prefixes: []string{"perl"}, extension: ".pl"},
+	{prefixes: []string{"zsh"}, extension: ".zsh"},
+	{prefixes: []string{"bash", "sh"}, extension: ".sh"},
+}
+
+// IsSupported: checks if the given extension (with the leading dot) is in the supported list
+func IsSupported(ext string) bool {
+	ext = strings.ToLower(ext)
+	for _, supported := range SupportedExtensions() {
+		if supported == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FromContent: guesses a file extension for the given content, falling back to DefaultExtension.
+// The result is always one of the SupportedExtensions.
+func FromContent(content []byte) string {
+	ext := guessExtension(content)
+	if !IsSupported(ext) {
+		return DefaultExtension
+	}
+
+	return ext
+}
+
+// guessExtension inspects the start of the content to find its format
+func guessExtension(content []byte) string {
+	trimmed := bytes.TrimLeft(content, " \t\r\n\ufeff")
+	if len(trimmed) == 0 {
+		return DefaultExtension
+	}
+
+	if (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return ".json"
+	}
+
+	head := strings.ToLower(string(trimmed[:min(len(trimmed), 512)]))
+
+	if strings.HasPrefix(head, "#!") {
+		line, _, _ := strings.Cut(head[2:], "\n")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return DefaultExtension
+		}
+
+		// `#!/usr/bin/env python3` names the interpreter after env
+		interpreter := path.Base(fields[0])
+		if interpreter == "env" && len(fields) > 1 {
+			interpreter = fields[1]
+		}
+
+		for _, rule := range shebangRules {
+			for _, prefix := range rule.prefixes {
+				if strings.HasPrefix(interpreter, prefix) {
+					return rule.extension
+				}
+			}
+		}
+
+		return DefaultExtension
+	}
+
+	for _, rule := range contentRules {
+		for _, prefix := range rule.prefixes {
+			if strings.HasPrefix(head, strings.ToLower(prefix)) {
+				return rule.extension
+			}
+		}
+	}
+
+	return DefaultExtension
+}
@@ -0,0 +1,228 @@
+package glob
+
+import (
+	"context"
+	"errors"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"zed-cli-win-unofficial/internal/pathutil"
+)
+
+// caseInsensitive matches names the way the filesystem compares them, Windows ignores case
+var caseInsensitive = runtime.GOOS == "windows"
+
+// HasMeta reports whether the argument contains any glob syntax: *, ?, [...] or {a,b}.
+// The `?` of an extended-length prefix like \\?\C:\ is part of the path, not a wildcard.
+func HasMeta(arg string) bool {
+	return strings.ContainsAny(pathutil.Display(arg), "*?[{")
+}
+
+// Expand returns the paths matching the pattern, sorted and without duplicates.
+// Besides *, ? and [...] within a name, `**` matches any number of folders and {a,b} expands to each alternative.
+// Folders are read with the pathutil deadline, a location that doesn't respond is an error rather than no match.
+func Expand(ctx context.Context, pattern string) ([]string, error) {
+	pattern = pathutil.Display(pattern)
+	matches := []string{}
+	seen := map[string]bool{}
+
+	for _, alternative := range ExpandBraces(pattern) {
+		alternativeMatches, err := expandOne(ctx, alternative)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, match := range alternativeMatches {
+			key := match
+			if caseInsensitive {
+				key = strings.ToLower(match)
+			}
+
+			if !seen[key] {
+				seen[key] = true
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// ExpandBraces expands brace sets, eg: `src/{api,web}/*.go` becomes `src/api/*.go` and `src/web/*.go`.
+// Braces without a comma, or without a closing brace, are kept as they are.
+func ExpandBraces(pattern string) []string {
+	for open := strings.IndexByte(pattern, '{'); open >= 0; {
+		end, alternatives := splitBraces(pattern[open+1:])
+		if end >= 0 && len(alternatives) > 1 {
+			prefix, suffix := pattern[:open], pattern[open+1+end+1:]
+
+			expanded := []string{}
+			for _, alternative := range alternatives {
+				expanded = append(expanded, ExpandBraces(prefix+alternative+suffix)...)
+			}
+
+			return expanded
+		}
+
+		next := strings.IndexByte(pattern[open+1:], '{')
+		if next < 0 {
+			break
+		}
+
+		open += 1 + next
+	}
+
+	return []string{pattern}
+}
+
+// splitBraces finds the brace closing the set that s starts inside of, and splits the set at its top-level commas
+func splitBraces(s string) (int, []string) {
+	depth, start := 0, 0
+	alternatives := []string{}
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i, append(alternatives, s[start:i])
+			}
+
+			depth--
+		case ',':
+			if depth == 0 {
+				alternatives = append(alternatives, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return -1, nil
+}
+
+// expandOne expands a pattern without braces, starting from the part of the path that has no glob syntax
+func expandOne(ctx context.Context, pattern string) ([]string, error) {
+	volume := filepath.VolumeName(pattern)
+	rest := pattern[len(volume):]
+
+	base := volume
+	if strings.HasPrefix(rest, `\`) || strings.HasPrefix(rest, "/") {
+		base += string(filepath.Separator)
+	}
+
+	segments := strings.FieldsFunc(rest, func(r rune) bool { return r == '\\' || r == '/' })
+
+	literal := 0
+	for literal < len(segments) && !HasMeta(segments[literal]) {
+		literal++
+	}
+
+	base = filepath.Join(append([]string{base}, segments[:literal]...)...)
+	if base == "" {
+		base = "."
+	}
+
+	return walk(ctx, base, segments[literal:])
+}
+
+// walk matches the remaining segments against the entries below dir.
+// Missing or unreadable folders just don't match, only giving up (timeout or Ctrl+C) stops the walk.
+func walk(ctx context.Context, dir string, segments []string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		_, err := pathutil.Probe(ctx, dir)
+		switch {
+		case err == nil:
+			return []string{dir}, nil
+		case gaveUp(err):
+			return nil, err
+		default:
+			return nil, nil
+		}
+	}
+
+	segment := segments[0]
+	if !HasMeta(segment) {
+		return walk(ctx, filepath.Join(dir, segment), segments[1:])
+	}
+
+	entries, err := pathutil.List(ctx, dir)
+	if err != nil {
+		if gaveUp(err) {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	matches := []string{}
+
+	if segment == "**" {
+		// `**` stands for no folder at all as well as for any depth of folders
+		here, err := walk(ctx, dir, segments[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, here...)
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			below, err := walk(ctx, filepath.Join(dir, entry.Name()), segments)
+			if err != nil {
+				return nil, err
+			}
+
+			matches = append(matches, below...)
+		}
+
+		return matches, nil
+	}
+
+	for _, entry := range entries {
+		if !matchName(segment, entry.Name()) {
+			continue
+		}
+
+		if len(segments) == 1 {
+			matches = append(matches, filepath.Join(dir, entry.Name()))
+			continue
+		}
+
+		if entry.IsDir() {
+			below, err := walk(ctx, filepath.Join(dir, entry.Name()), segments[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			matches = append(matches, below...)
+		}
+	}
+
+	return matches, nil
+}
+
+// gaveUp reports whether a lookup failed because the location stopped responding or the launch was cancelled
+func gaveUp(err error) bool {
+	return errors.Is(err, pathutil.ErrNotResponding) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// matchName matches a single name against a single pattern segment, `[!a-z]` is accepted for a negated set
+func matchName(pattern string, name string) bool {
+	pattern = strings.ReplaceAll(pattern, "[!", "[^")
+	if caseInsensitive {
+		pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	}
+
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
@@ -0,0 +1,101 @@
+package glob
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestHasMeta(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want bool
+	}{
+		{arg: `C:\code\api`, want: false},
+		{arg: `src\*.go`, want: true},
+		{arg: `notes?.md`, want: true},
+		{arg: `notes[1].md`, want: true},
+		{arg: `src\{api,web}`, want: true},
+		{arg: `\\?\C:\deep\new`, want: false},
+		{arg: `\\?\UNC\server\share\new`, want: false},
+		{arg: `\\?\C:\deep\*.go`, want: true},
+		{arg: `\\?\UNC\server\share\?.md`, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := HasMeta(tt.arg); got != tt.want {
+			t.Errorf("HasMeta(%q) = %v, want %v", tt.arg, got, tt.want)
+		}
+	}
+}
+
+// tree creates the files below a temp folder and returns the folder
+func tree(t *testing.T, files ...string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func TestExpand(t *testing.T) {
+	root := tree(t, "main.go", "util.go", "notes.md", "src/api/a.go", "src/web/b.ts", "src/web/deep/c.ts")
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "*.go", want: []string{"main.go", "util.go"}},
+		{pattern: "?ain.go", want: []string{"main.go"}},
+		{pattern: "[mn]*", want: []string{"main.go", "notes.md"}},
+		{pattern: "[!mn]*", want: []string{"src", "util.go"}},
+		{pattern: "*.{go,md}", want: []string{"main.go", "notes.md", "util.go"}},
+		{pattern: "src/*/a.go", want: []string{"src/api/a.go"}},
+		{pattern: "src/**/*.ts", want: []string{"src/web/b.ts", "src/web/deep/c.ts"}},
+		{pattern: "**/a.go", want: []string{"src/api/a.go"}},
+		{pattern: "*.rs", want: []string{}},
+		{pattern: "missing/*.go", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := Expand(context.Background(), filepath.Join(root, filepath.FromSlash(tt.pattern)))
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+
+			want := []string{}
+			for _, path := range tt.want {
+				want = append(want, filepath.Join(root, filepath.FromSlash(path)))
+			}
+
+			if !slices.Equal(got, want) {
+				t.Errorf("Expand(%q) = %q, want %q", tt.pattern, got, want)
+			}
+		})
+	}
+}
+
+func TestExpandCancelled(t *testing.T) {
+	root := tree(t, "src/api/a.go")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if got, err := Expand(ctx, filepath.Join(root, "**", "*.go")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expand() = %q, %v; want %v", got, err, context.Canceled)
+	}
+}
@@ -0,0 +1,175 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"zed-cli-win-unofficial/internal/pathutil"
+)
+
+// MaxEntries is how many projects the history keeps, older ones are dropped
+const MaxEntries int = 50
+
+// lockTimeout is how long Record waits for another zed process to finish writing the history
+const lockTimeout = 2 * time.Second
+
+// Entry is a project that was opened in Zed
+type Entry struct {
+	Path     string    `json:"path"`
+	OpenedAt time.Time `json:"openedAt"`
+}
+
+// Load reads the history at path, most recent first. A missing file is an empty history.
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Entry{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read history: %w", err)
+	}
+
+	entries := []Entry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("unable to parse history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Record moves the given targets to the top of the history at path.
+// Paths are made absolute and compared case-insensitively, like Windows does, so each project is only listed once.
+func Record(path string, targets []string, openedAt time.Time) error {
+	return withLock(path, func() error {
+		entries, err := Load(path)
+		if err != nil {
+			// A corrupt history isn't worth failing a launch over, it's rebuilt from here.
+			entries = []Entry{}
+		}
+
+		recorded := []Entry{}
+		for _, target := range targets {
+			normalized, err := Normalize(target)
+			if err != nil || isTempPath(normalized) {
+				continue
+			}
+
+			recorded = append(recorded, Entry{Path: normalized, OpenedAt: openedAt})
+		}
+
+		return save(path, merge(recorded, entries))
+	})
+}
+
+// Clear removes every entry from the history at path
+func Clear(path string) error {
+	return withLock(path, func() error {
+		return save(path, []Entry{})
+	})
+}
+
+// Normalize turns a path into the form it's stored in the history
+func Normalize(path string) (string, error) {
+	absPath, err := filepath.Abs(pathutil.Display(path))
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve path: %w", err)
+	}
+
+	return pathutil.Clean(absPath), nil
+}
+
+// merge puts the new entries ahead of the existing ones, dropping duplicates and anything past MaxEntries
+func merge(recorded []Entry, existing []Entry) []Entry {
+	merged := []Entry{}
+	seen := map[string]bool{}
+
+	for _, entry := range append(recorded, existing...) {
+		key := strings.ToLower(entry.Path)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		merged = append(merged, entry)
+
+		if len(merged) == MaxEntries {
+			break
+		}
+	}
+
+	return merged
+}
+
+// isTempPath reports whether the path lives in the temp folder, eg: captured stdin, which isn't worth remembering
+func isTempPath(path string) bool {
+	tempDir := filepath.Clean(os.TempDir()) + string(filepath.Separator)
+	return strings.HasPrefix(strings.ToLower(path), strings.ToLower(tempDir))
+}
+
+// save writes the entries to a temp file and renames it over the history, so readers never see a half written file
+func save(path string, entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode history: %w", err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to write history: %w", err)
+	}
+
+	_, writeErr := tempFile.Write(data)
+	closeErr := tempFile.Close()
+
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tempFile.Name())
+		return fmt.Errorf("unable to write history: %w", err)
+	}
+
+	if err := os.Rename(tempFile.Name(), path); err != nil {
+		os.Remove(tempFile.Name())
+		return fmt.Errorf("unable to write history: %w", err)
+	}
+
+	return nil
+}
+
+// withLock runs fn while holding the lock on the file next to the history, so concurrent launches don't lose each other's entries.
+// It's a lock of the OS rather than the file's existence, so a process killed while holding it can't leave it behind.
+func withLock(path string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("unable to create history directory: %w", err)
+	}
+
+	lockPath := path + ".lock"
+	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to lock history: %w", err)
+	}
+	defer lockFile.Close()
+
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		locked, err := tryLock(lockFile)
+		if err != nil {
+			return fmt.Errorf("unable to lock history: %w", err)
+		}
+
+		if locked {
+			defer unlock(lockFile)
+			return fn()
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("history is locked by another process: %s", lockPath)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
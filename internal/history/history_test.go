@@ -0,0 +1,195 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// normalized is the form a target is stored in, failing the test when it can't be resolved
+func normalized(t *testing.T, target string) string {
+	t.Helper()
+
+	path, err := Normalize(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// paths lists the paths of the entries in order
+func paths(entries []Entry) []string {
+	result := []string{}
+	for _, entry := range entries {
+		result = append(result, entry.Path)
+	}
+
+	return result
+}
+
+func TestMerge(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		recorded []string
+		existing []string
+		want     []string
+	}{
+		{"new goes first", []string{`C:\b`}, []string{`C:\a`}, []string{`C:\b`, `C:\a`}},
+		{"reopened moves up", []string{`C:\a`}, []string{`C:\b`, `C:\a`}, []string{`C:\a`, `C:\b`}},
+		{"case-insensitive", []string{`c:\CODE\api`}, []string{`C:\code\api`, `C:\b`}, []string{`c:\CODE\api`, `C:\b`}},
+		{"duplicates in one launch", []string{`C:\a`, `C:\A`}, nil, []string{`C:\a`}},
+		{"empty", nil, nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorded, existing := []Entry{}, []Entry{}
+			for _, path := range tt.recorded {
+				recorded = append(recorded, Entry{Path: path, OpenedAt: at})
+			}
+
+			for _, path := range tt.existing {
+				existing = append(existing, Entry{Path: path, OpenedAt: at.Add(-time.Hour)})
+			}
+
+			if got := paths(merge(recorded, existing)); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("merge() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeCapsEntries(t *testing.T) {
+	existing := []Entry{}
+	for i := range MaxEntries {
+		existing = append(existing, Entry{Path: fmt.Sprintf(`C:\old\%d`, i)})
+	}
+
+	merged := merge([]Entry{{Path: `C:\new`}}, existing)
+	if len(merged) != MaxEntries {
+		t.Fatalf("merge() kept %d entries, want %d", len(merged), MaxEntries)
+	}
+
+	if merged[0].Path != `C:\new` || merged[MaxEntries-1].Path != fmt.Sprintf(`C:\old\%d`, MaxEntries-2) {
+		t.Errorf("merge() = %q ... %q, want the new entry first and the oldest dropped", merged[0].Path, merged[MaxEntries-1].Path)
+	}
+}
+
+func TestRecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if entries, err := Load(path); err != nil || len(entries) != 0 {
+		t.Fatalf("Load() of a missing history = %v, %v; want empty", entries, err)
+	}
+
+	// Projects are named relative to the package folder, the temp folder itself is never recorded.
+	if err := Record(path, []string{"api", "web"}, at); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+
+	if err := Record(path, []string{"api", filepath.Join(os.TempDir(), "zed-stdin-1.txt")}, at.Add(time.Minute)); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	want := []string{normalized(t, "api"), normalized(t, "web")}
+	if got := paths(entries); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Load() = %q, want %q", got, want)
+	}
+
+	if !entries[0].OpenedAt.Equal(at.Add(time.Minute)) {
+		t.Errorf("OpenedAt = %s, want the second launch", entries[0].OpenedAt)
+	}
+
+	if err := Clear(path); err != nil {
+		t.Fatalf("Clear() = %v", err)
+	}
+
+	if entries, err := Load(path); err != nil || len(entries) != 0 {
+		t.Errorf("Load() after Clear() = %v, %v; want empty", entries, err)
+	}
+}
+
+func TestRecordRebuildsCorruptHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("Load() of a corrupt history = nil, want an error")
+	}
+
+	if err := Record(path, []string{"api"}, time.Now()); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+
+	if entries, err := Load(path); err != nil || len(entries) != 1 {
+		t.Errorf("Load() = %v, %v; want the one new entry", entries, err)
+	}
+}
+
+func TestRecordIgnoresLeftoverLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	// A lock file left by a killed process isn't a held lock.
+	if err := os.WriteFile(path+".lock", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := Record(path, []string{"api"}, time.Now()); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > lockTimeout/2 {
+		t.Errorf("Record() waited %s on a leftover lock file", elapsed)
+	}
+}
+
+func TestRecordConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	const launches = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, launches)
+
+	for i := range launches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- Record(path, []string{fmt.Sprintf("project-%d", i)}, time.Now())
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Record() = %v", err)
+		}
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	// Every launch's entry survives, none was lost to another launch writing at the same time.
+	if len(entries) != launches {
+		t.Errorf("Load() has %d entries, want %d", len(entries), launches)
+	}
+}
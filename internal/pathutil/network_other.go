@@ -0,0 +1,8 @@
+//go:build !windows
+
+package pathutil
+
+// IsNetworkPath reports whether the path is on a network share, only UNC paths can be told apart here
+func IsNetworkPath(path string) bool {
+	return IsUNC(path)
+}
@@ -0,0 +1,26 @@
+package pathutil
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// IsNetworkPath reports whether the path is on a network share, either by UNC path or through a mapped drive
+func IsNetworkPath(path string) bool {
+	if IsUNC(path) {
+		return true
+	}
+
+	volume := filepath.VolumeName(Clean(path))
+	if len(volume) != 2 {
+		return false
+	}
+
+	root, err := windows.UTF16PtrFromString(volume + `\`)
+	if err != nil {
+		return false
+	}
+
+	return windows.GetDriveType(root) == windows.DRIVE_REMOTE
+}
@@ -0,0 +1,105 @@
+package pathutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// extendedPrefix lifts the MAX_PATH limit of Win32 calls, eg: \\?\C:\very\deep\path
+	extendedPrefix string = `\\?\`
+	// extendedUNCPrefix is extendedPrefix for network shares, eg: \\?\UNC\server\share\path
+	extendedUNCPrefix string = `\\?\UNC\`
+)
+
+// longPathThreshold is where paths start to need extendedPrefix. MAX_PATH is 260, but folders are refused
+// from 248 on since a file name still has to fit inside them.
+const longPathThreshold = 248
+
+// Display removes the extended-length prefix, for messages and for programs that don't understand it (most of them)
+func Display(path string) string {
+	switch {
+	case len(path) >= len(extendedUNCPrefix) && strings.EqualFold(path[:len(extendedUNCPrefix)], extendedUNCPrefix):
+		return `\\` + path[len(extendedUNCPrefix):]
+	case strings.HasPrefix(path, extendedPrefix):
+		return path[len(extendedPrefix):]
+	default:
+		return path
+	}
+}
+
+// Clean normalizes a path the same way everywhere: no extended-length prefix, one kind of separator,
+// no `.`/`..` elements and no trailing separator, except on roots like C:\ and \\server\share\.
+func Clean(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	return filepath.Clean(Display(path))
+}
+
+// HasTrailingSeparator reports whether the path ends in a separator, which marks it as a folder
+func HasTrailingSeparator(path string) bool {
+	return strings.HasSuffix(path, `\`) || strings.HasSuffix(path, "/")
+}
+
+// IsUNC reports whether the path is on a network share, eg: \\server\share\repo
+func IsUNC(path string) bool {
+	path = Display(path)
+	return len(path) > 2 && isSeparator(path[0]) && isSeparator(path[1]) && path[2] != '?' && path[2] != '.'
+}
+
+// Extended returns the form of an absolute path that Win32 accepts past MAX_PATH.
+// Short, relative and already prefixed paths are returned as they are.
+func Extended(path string) string {
+	if len(path) < longPathThreshold || !filepath.IsAbs(path) || strings.HasPrefix(path, extendedPrefix) || strings.HasPrefix(path, `\\.\`) {
+		return path
+	}
+
+	// Windows skips all normalization for prefixed paths, so `..` and `/` have to be dealt with first.
+	cleaned := filepath.Clean(path)
+	if IsUNC(cleaned) {
+		return extendedUNCPrefix + strings.TrimLeft(cleaned, `\/`)
+	}
+
+	return extendedPrefix + cleaned
+}
+
+// Stat is os.Stat for paths of any length
+func Stat(path string) (os.FileInfo, error) {
+	return os.Stat(Extended(path))
+}
+
+// ReadDir is os.ReadDir for paths of any length
+func ReadDir(path string) ([]os.DirEntry, error) {
+	return os.ReadDir(Extended(path))
+}
+
+// Exists checks if the file or folder exists, for paths of any length.
+// It gives up after the default deadline, an unreachable location counts as missing.
+func Exists(path string) bool {
+	exists, _ := defaultProber.Exists(context.Background(), path)
+	return exists
+}
+
+// Mkdir is os.Mkdir for paths of any length
+func Mkdir(path string, perm os.FileMode) error {
+	return os.Mkdir(Extended(path), perm)
+}
+
+// MkdirAll is os.MkdirAll for paths of any length
+func MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(Extended(path), perm)
+}
+
+// OpenFile is os.OpenFile for paths of any length
+func OpenFile(path string, flag int, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(Extended(path), flag, perm)
+}
+
+// isSeparator checks if the byte is a path separator on Windows
+func isSeparator(b byte) bool {
+	return b == '\\' || b == '/'
+}
@@ -0,0 +1,80 @@
+package pathutil
+
+import (
+	"strings"
+)
+
+// wslHost is the UNC host Windows exposes WSL distros under
+const wslHost string = `\\wsl.localhost`
+
+// FromPOSIX converts a path written the way Git Bash, Cygwin or WSL spell it into a Windows path:
+//
+//	/c/Users/me/src       -> C:\Users\me\src                 (Git Bash / MSYS)
+//	/cygdrive/c/Users/me  -> C:\Users\me                     (Cygwin)
+//	/mnt/d/work           -> D:\work                         (WSL, Windows drives)
+//	/home/me/src          -> \\wsl.localhost\<distro>\home\me\src (WSL, inside the distro)
+//
+// Paths inside a distro are only converted when distro is known. ok is false for anything that isn't a POSIX path.
+func FromPOSIX(path string, distro string) (string, bool) {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return "", false
+	}
+
+	segments := []string{}
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	// A trailing slash says "folder", which matters when the path gets created.
+	trailing := ""
+	if strings.HasSuffix(path, "/") && len(path) > 1 {
+		trailing = `\`
+	}
+
+	for _, prefix := range [][]string{{"cygdrive"}, {"mnt"}, {}} {
+		if drive, rest, ok := driveAfter(segments, prefix); ok {
+			if len(rest) == 0 {
+				return strings.ToUpper(drive) + `:\`, true
+			}
+
+			return strings.ToUpper(drive) + `:\` + strings.Join(rest, `\`) + trailing, true
+		}
+	}
+
+	if distro == "" {
+		return "", false
+	}
+
+	if len(segments) == 0 {
+		return wslHost + `\` + distro + `\`, true
+	}
+
+	return wslHost + `\` + distro + `\` + strings.Join(segments, `\`) + trailing, true
+}
+
+// driveAfter checks if the segments are the prefix followed by a single drive letter, eg: `mnt`, `d`, ...
+func driveAfter(segments []string, prefix []string) (string, []string, bool) {
+	if len(segments) <= len(prefix) {
+		return "", nil, false
+	}
+
+	for i, name := range prefix {
+		if segments[i] != name {
+			return "", nil, false
+		}
+	}
+
+	drive := segments[len(prefix)]
+	if len(drive) != 1 || !isLetter(drive[0]) {
+		return "", nil, false
+	}
+
+	return drive, segments[len(prefix)+1:], true
+}
+
+// isLetter checks if the byte is an ASCII letter
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
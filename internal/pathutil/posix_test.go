@@ -0,0 +1,45 @@
+package pathutil
+
+import "testing"
+
+func TestFromPOSIX(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		distro string
+		want   string
+		wantOK bool
+	}{
+		{name: "git bash", path: "/c/Users/me/src", want: `C:\Users\me\src`, wantOK: true},
+		{name: "git bash upper drive", path: "/D/work", want: `D:\work`, wantOK: true},
+		{name: "cygwin", path: "/cygdrive/c/Users/me", want: `C:\Users\me`, wantOK: true},
+		{name: "wsl mount", path: "/mnt/d/work", want: `D:\work`, wantOK: true},
+		{name: "wsl mount with distro", path: "/mnt/d/work", distro: "Ubuntu", want: `D:\work`, wantOK: true},
+		{name: "bare drive", path: "/c", want: `C:\`, wantOK: true},
+		{name: "bare drive with slash", path: "/c/", want: `C:\`, wantOK: true},
+		{name: "bare cygwin drive", path: "/cygdrive/e", want: `E:\`, wantOK: true},
+		{name: "trailing slash", path: "/c/code/new/", want: `C:\code\new\`, wantOK: true},
+		{name: "repeated slashes", path: "/c//code///api", want: `C:\code\api`, wantOK: true},
+		{name: "distro home", path: "/home/me/src", distro: "Ubuntu", want: `\\wsl.localhost\Ubuntu\home\me\src`, wantOK: true},
+		{name: "distro trailing slash", path: "/home/me/", distro: "Ubuntu", want: `\\wsl.localhost\Ubuntu\home\me\`, wantOK: true},
+		{name: "distro root", path: "/", distro: "Debian", want: `\\wsl.localhost\Debian\`, wantOK: true},
+		{name: "distro mnt without drive", path: "/mnt/data", distro: "Ubuntu", want: `\\wsl.localhost\Ubuntu\mnt\data`, wantOK: true},
+		{name: "no distro", path: "/home/me/src"},
+		{name: "no distro root", path: "/"},
+		{name: "long first segment", path: "/cc/src"},
+		{name: "unc style", path: "//server/share"},
+		{name: "unc style with distro", path: "//server/share", distro: "Ubuntu"},
+		{name: "relative", path: "c/src"},
+		{name: "windows", path: `C:\src`},
+		{name: "empty", path: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FromPOSIX(tt.path, tt.distro)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("FromPOSIX(%q, %q) = %q, %v; want %q, %v", tt.path, tt.distro, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package pathutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotResponding is returned when a path check runs past its deadline, eg: a disconnected mapped drive or a sleeping NAS
+var ErrNotResponding = errors.New("location not responding")
+
+// DefaultTimeout is how long a path check may take before it's given up on
+const DefaultTimeout = 5 * time.Second
+
+// Prober checks paths with a deadline, so an unreachable share can't hang the terminal.
+// A location that ran out of time once is reported as not responding right away afterwards,
+// so the many checks of a single launch only wait for it once.
+type Prober struct {
+	// Stat looks up the path, swapped for a slow fake to test the deadline
+	Stat func(path string) (os.FileInfo, error)
+	// ReadDir lists a folder, swapped for a slow fake like Stat
+	ReadDir func(path string) ([]os.DirEntry, error)
+	// Timeout bounds every check, 0 waits as long as the context allows
+	Timeout time.Duration
+
+	mu sync.Mutex
+	// unreachable holds the error of every location that timed out, by lower-cased location
+	unreachable map[string]error
+}
+
+// NewProber creates a Prober for the real filesystem
+func NewProber(timeout time.Duration) *Prober {
+	return &Prober{Stat: Stat, ReadDir: ReadDir, Timeout: timeout}
+}
+
+// defaultProber backs Probe, List and Exists
+var defaultProber = NewProber(DefaultTimeout)
+
+// SetTimeout changes the deadline of Probe, List and Exists, eg: from the configured path timeout
+func SetTimeout(timeout time.Duration) {
+	defaultProber.Timeout = timeout
+}
+
+// Probe stats the path with the default deadline
+func Probe(ctx context.Context, path string) (os.FileInfo, error) {
+	return defaultProber.Probe(ctx, path)
+}
+
+// List lists the folder with the default deadline
+func List(ctx context.Context, path string) ([]os.DirEntry, error) {
+	return defaultProber.List(ctx, path)
+}
+
+// Probe stats the path, giving up with ErrNotResponding once the deadline passes.
+// A stat stuck in the kernel can't be cancelled, it's left to finish in the background.
+func (p *Prober) Probe(ctx context.Context, path string) (os.FileInfo, error) {
+	return bounded(ctx, p, path, p.Stat)
+}
+
+// List reads the folder's entries, giving up with ErrNotResponding once the deadline passes like Probe
+func (p *Prober) List(ctx context.Context, path string) ([]os.DirEntry, error) {
+	return bounded(ctx, p, path, p.ReadDir)
+}
+
+// bounded runs a lookup of path within the prober's deadline.
+// Once a location timed out, lookups anywhere on it fail right away with the same error.
+func bounded[T any](ctx context.Context, p *Prober, path string, lookup func(string) (T, error)) (T, error) {
+	var zero T
+
+	key := strings.ToLower(location(path))
+	if err := p.unreachableErr(key); err != nil {
+		return zero, err
+	}
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	// Buffered, so the lookup can still hand over its result after we stopped waiting.
+	done := make(chan result, 1)
+	go func() {
+		value, err := lookup(path)
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err := fmt.Errorf("%w: %s (gave up after %s)", ErrNotResponding, location(path), p.Timeout)
+			if IsNetworkPath(path) {
+				err = fmt.Errorf("network %w", err)
+			}
+
+			p.markUnreachable(key, err)
+			return zero, err
+		}
+
+		return zero, ctx.Err()
+	}
+}
+
+// unreachableErr returns the error a location timed out with earlier, nil when it didn't
+func (p *Prober) unreachableErr(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.unreachable[key]
+}
+
+// markUnreachable remembers that a location timed out
+func (p *Prober) markUnreachable(key string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.unreachable == nil {
+		p.unreachable = map[string]error{}
+	}
+
+	p.unreachable[key] = err
+}
+
+// Exists checks if the path exists. Unlike os.Stat errors, an unreachable location is reported as an error.
+func (p *Prober) Exists(ctx context.Context, path string) (bool, error) {
+	_, err := p.Probe(ctx, path)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNotResponding), errors.Is(err, context.Canceled):
+		return false, err
+	default:
+		return false, nil
+	}
+}
+
+// location names the drive or share a path lives on, which is what's actually not responding
+func location(path string) string {
+	if volume := filepath.VolumeName(Display(path)); volume != "" {
+		return volume
+	}
+
+	return path
+}
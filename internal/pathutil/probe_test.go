@@ -0,0 +1,149 @@
+package pathutil
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowStat never answers until release is closed, like a stat on a disconnected mapped drive
+func slowStat(calls *atomic.Int32, release <-chan struct{}) func(string) (os.FileInfo, error) {
+	return func(path string) (os.FileInfo, error) {
+		calls.Add(1)
+		<-release
+		return nil, fs.ErrNotExist
+	}
+}
+
+func TestProberTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	calls := atomic.Int32{}
+	prober := &Prober{Stat: slowStat(&calls, release), Timeout: 20 * time.Millisecond}
+
+	start := time.Now()
+	_, err := prober.Probe(context.Background(), "offline/repo")
+	if !errors.Is(err, ErrNotResponding) {
+		t.Fatalf("Probe() error = %v, want %v", err, ErrNotResponding)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Probe() took %s, want about %s", elapsed, prober.Timeout)
+	}
+
+	exists, err := prober.Exists(context.Background(), "offline/repo")
+	if exists || !errors.Is(err, ErrNotResponding) {
+		t.Errorf("Exists() = %v, %v; want false, %v", exists, err, ErrNotResponding)
+	}
+
+	// The second check is answered from the first timeout, without waiting again.
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Stat called %d times, want 1", got)
+	}
+}
+
+func TestProberAnswersInTime(t *testing.T) {
+	prober := &Prober{Stat: func(string) (os.FileInfo, error) { return nil, fs.ErrNotExist }, Timeout: time.Second}
+
+	if _, err := prober.Probe(context.Background(), "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Probe() error = %v, want %v", err, fs.ErrNotExist)
+	}
+
+	if exists, err := prober.Exists(context.Background(), "missing"); exists || err != nil {
+		t.Errorf("Exists() = %v, %v; want false, nil", exists, err)
+	}
+
+	if exists, err := NewProber(time.Second).Exists(context.Background(), t.TempDir()); !exists || err != nil {
+		t.Errorf("Exists() = %v, %v; want true, nil", exists, err)
+	}
+}
+
+func TestProberCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	calls := atomic.Int32{}
+	prober := &Prober{Stat: slowStat(&calls, release)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := prober.Probe(ctx, "offline/repo"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Probe() error = %v, want %v", err, context.Canceled)
+	}
+
+	// A cancelled check says nothing about the location, so the next one still tries.
+	if prober.unreachableErr("offline/repo") != nil {
+		t.Error("cancelled location was remembered as unreachable")
+	}
+}
+
+func TestProberNamesNetworkLocations(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	calls := atomic.Int32{}
+	prober := &Prober{Stat: slowStat(&calls, release), Timeout: 10 * time.Millisecond}
+
+	_, err := prober.Probe(context.Background(), `\\nas\share\repo`)
+	if !errors.Is(err, ErrNotResponding) || !strings.HasPrefix(err.Error(), "network location not responding") {
+		t.Errorf("Probe() error = %v, want a network location not responding", err)
+	}
+
+	_, err = prober.Probe(context.Background(), "offline/repo")
+	if !errors.Is(err, ErrNotResponding) || strings.HasPrefix(err.Error(), "network") {
+		t.Errorf("Probe() error = %v, want a location not responding", err)
+	}
+}
+
+func TestProberListSharesTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	lists := atomic.Int32{}
+	prober := &Prober{
+		Stat: func(string) (os.FileInfo, error) { return nil, fs.ErrNotExist },
+		ReadDir: func(string) ([]os.DirEntry, error) {
+			lists.Add(1)
+			<-release
+			return nil, nil
+		},
+		Timeout: 20 * time.Millisecond,
+	}
+
+	if _, err := prober.List(context.Background(), "offline/repo"); !errors.Is(err, ErrNotResponding) {
+		t.Fatalf("List() error = %v, want %v", err, ErrNotResponding)
+	}
+
+	// The folder that didn't answer takes every later check on its location with it, stats included.
+	if _, err := prober.List(context.Background(), "offline/repo"); !errors.Is(err, ErrNotResponding) {
+		t.Errorf("second List() error = %v, want %v", err, ErrNotResponding)
+	}
+
+	if _, err := prober.Probe(context.Background(), "offline/repo"); !errors.Is(err, ErrNotResponding) {
+		t.Errorf("Probe() error = %v, want %v", err, ErrNotResponding)
+	}
+
+	if got := lists.Load(); got != 1 {
+		t.Errorf("ReadDir called %d times, want 1", got)
+	}
+}
+
+func TestProberList(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := NewProber(time.Second).List(context.Background(), dir)
+	if err != nil || len(entries) != 1 || entries[0].Name() != "a.txt" {
+		t.Errorf("List() = %v, %v; want [a.txt], nil", entries, err)
+	}
+}
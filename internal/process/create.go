@@ -0,0 +1,112 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"zed-cli-win-unofficial/internal/fileext"
+	"zed-cli-win-unofficial/internal/pathutil"
+	"zed-cli-win-unofficial/internal/utils"
+)
+
+// CreatePolicy decides what happens when a launch path doesn't exist
+type CreatePolicy string
+
+const (
+	// CreateAlways creates missing paths without asking, the CLI's long-standing behaviour
+	CreateAlways CreatePolicy = "always"
+	// CreateAsk asks before creating anything
+	CreateAsk CreatePolicy = "ask"
+	// CreateNever refuses to open missing paths
+	CreateNever CreatePolicy = "never"
+)
+
+// ParseCreatePolicy parses a policy name from the config, an empty value means CreateAlways
+func ParseCreatePolicy(value string) (CreatePolicy, error) {
+	switch policy := CreatePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return CreateAlways, nil
+	case CreateAlways, CreateAsk, CreateNever:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown create policy '%s' (expected always, ask or never)", value)
+	}
+}
+
+// looksLikeFile decides from its name whether a missing path should be created as a file or a folder.
+// Names ending in a separator are always folders, names with a known extension (or a known name like Makefile) are files.
+func looksLikeFile(path string) bool {
+	if pathutil.HasTrailingSeparator(path) {
+		return false
+	}
+
+	name := filepath.Base(path)
+	return fileext.IsSupported(filepath.Ext(name)) || fileext.IsSupported("."+name)
+}
+
+// createMissingPath creates a launch path that doesn't exist yet according to the create policy
+func createMissingPath(ctx context.Context, absPath string, isFile bool, opts LaunchOptions) error {
+	kind := "folder"
+	if isFile {
+		kind = "file"
+	}
+
+	if opts.Create == CreateNever {
+		return fmt.Errorf("path does not exist: %s", absPath)
+	}
+
+	parent := filepath.Dir(absPath)
+	_, err := pathutil.Probe(ctx, parent)
+	if errors.Is(err, pathutil.ErrNotResponding) || errors.Is(err, context.Canceled) {
+		return fmt.Errorf("unable to access parent folder: %w", err)
+	}
+
+	parentMissing := os.IsNotExist(err)
+
+	if parentMissing && !opts.CreateParents {
+		return fmt.Errorf("parent folder does not exist: %s (use --parents to create it)", parent)
+	}
+
+	// A dry run only reports what would be created, there's nothing to confirm yet.
+	if opts.plan != nil {
+		if parentMissing {
+			opts.plan.addCreation("folder", parent)
+		}
+
+		opts.plan.addCreation(kind, absPath)
+		return nil
+	}
+
+	if opts.Create == CreateAsk && !utils.Confirm(fmt.Sprintf("%s doesn't exist, create it as a new %s?", absPath, kind)) {
+		return fmt.Errorf("%s not created: %s", kind, absPath)
+	}
+
+	if parentMissing {
+		if err := pathutil.MkdirAll(parent, 0755); err != nil {
+			return fmt.Errorf("unable to create parent folder: %w", err)
+		}
+
+		utils.Info("📁 Created parent folder: %s\n", parent)
+	}
+
+	if !isFile {
+		if err := pathutil.Mkdir(absPath, 0755); err != nil {
+			return fmt.Errorf("unable to create project folder: %w", err)
+		}
+
+		utils.Info("📁 Created new folder: %s\n", absPath)
+		return nil
+	}
+
+	file, err := pathutil.OpenFile(absPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to create file: %w", err)
+	}
+
+	file.Close()
+	utils.Info("📄 Created new file: %s\n", absPath)
+	return nil
+}
@@ -0,0 +1,48 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"zed-cli-win-unofficial/internal/clierr"
+	"zed-cli-win-unofficial/internal/pathutil"
+	"zed-cli-win-unofficial/internal/utils"
+
+	"github.com/hashicorp/go-version"
+)
+
+var diffFlag = zedFlag{name: "--diff", minVersion: "0.188.0"}
+
+// diffArgs builds the Zed arguments that show two files side by side.
+// Zed builds without a diff view get both files opened in one window instead.
+func diffArgs(ctx context.Context, paths []string, zedVersion *version.Version) ([]string, error) {
+	if len(paths) != 2 {
+		return nil, clierr.New(clierr.Usage, "--diff needs exactly two files, got %d", len(paths))
+	}
+
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(pathutil.Display(path))
+		if err != nil {
+			return nil, clierr.New(clierr.InvalidPath, "unable to resolve path: %w", err)
+		}
+
+		info, err := pathutil.Probe(ctx, absPath)
+		if err != nil {
+			return nil, clierr.New(clierr.InvalidPath, "unable to diff %s: %w", path, err)
+		}
+
+		if info.IsDir() {
+			return nil, clierr.New(clierr.InvalidPath, "cannot diff a folder: %s", absPath)
+		}
+
+		resolved = append(resolved, absPath)
+	}
+
+	if !diffFlag.supportedBy(zedVersion) {
+		utils.Warning(fmt.Sprintf("Zed v%s has no diff view (requires v%s), opening both files instead", zedVersion.String(), diffFlag.minVersion))
+		return resolved, nil
+	}
+
+	return append([]string{diffFlag.name}, resolved...), nil
+}
@@ -0,0 +1,19 @@
+package process
+
+import "github.com/hashicorp/go-version"
+
+// zedFlag is a Zed command-line flag with the first Zed version that understands it
+type zedFlag struct {
+	name       string
+	minVersion string
+}
+
+// supportedBy checks if the given Zed version understands the flag.
+// A nil version means it couldn't be detected, in which case the flag is assumed to be supported.
+func (f zedFlag) supportedBy(zedVersion *version.Version) bool {
+	if zedVersion == nil {
+		return true
+	}
+
+	return !zedVersion.LessThan(version.Must(version.NewVersion(f.minVersion)))
+}
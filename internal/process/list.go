@@ -0,0 +1,92 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"zed-cli-win-unofficial/internal/pathutil"
+	"zed-cli-win-unofficial/internal/utils"
+)
+
+// ProcessInfo describes a running process
+type ProcessInfo struct {
+	// PID is the process identifier
+	PID int
+	// ExePath is the full path of the process executable, empty when it couldn't be read
+	ExePath string
+}
+
+// Lister enumerates the running processes
+type Lister interface {
+	// List returns the processes whose executable is named name (eg: zed.exe, ignoring case), all of them when it's empty
+	List(name string) ([]ProcessInfo, error)
+}
+
+// ProcFSLister lists processes from a procfs mount, it's the portable implementation used outside Windows
+type ProcFSLister struct {
+	// Root is the procfs mount point, usually /proc
+	Root string
+}
+
+// List reads the executable of every numeric entry under Root, skipping processes it isn't allowed to inspect
+func (l ProcFSLister) List(name string) ([]ProcessInfo, error) {
+	entries, err := os.ReadDir(l.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	processes := []ProcessInfo{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		exePath, err := os.Readlink(filepath.Join(l.Root, entry.Name(), "exe"))
+		if err != nil || (name != "" && !strings.EqualFold(filepath.Base(exePath), name)) {
+			continue
+		}
+
+		processes = append(processes, ProcessInfo{PID: pid, ExePath: exePath})
+	}
+
+	return processes, nil
+}
+
+// sameExecutable compares two executable paths the way Windows does, ignoring case, separator style
+// and extended-length prefixes
+func sameExecutable(a string, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	return strings.EqualFold(pathutil.Clean(a), pathutil.Clean(b))
+}
+
+// isZedRunning checks if the Zed executable at zedPath is currently running.
+// Matching by path instead of by process name keeps Zed Preview, renamed builds and other installs apart.
+func isZedRunning(lister Lister, zedPath string) (bool, error) {
+	if resolvedPath, err := filepath.EvalSymlinks(zedPath); err == nil {
+		zedPath = resolvedPath
+	}
+
+	if absPath, err := filepath.Abs(zedPath); err == nil {
+		zedPath = absPath
+	}
+
+	// Only processes with the same file name can be this Zed, the lister skips the rest without looking up their paths.
+	processes, err := lister.List(filepath.Base(pathutil.Clean(zedPath)))
+	if err != nil {
+		return false, err
+	}
+
+	for _, proc := range processes {
+		if sameExecutable(proc.ExePath, zedPath) {
+			utils.Debug("Zed is running with PID %d\n", proc.PID)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,6 @@
+//go:build !windows
+
+package process
+
+// defaultLister is the process lister used by LaunchZed
+var defaultLister Lister = ProcFSLister{Root: "/proc"}
@@ -0,0 +1,147 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeLister returns fixed processes and remembers the name it was asked for
+type fakeLister struct {
+	processes []ProcessInfo
+	err       error
+	asked     string
+}
+
+func (l *fakeLister) List(name string) ([]ProcessInfo, error) {
+	l.asked = name
+	return l.processes, l.err
+}
+
+func TestSameExecutable(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: `C:\Zed\zed.exe`, b: `C:\Zed\zed.exe`, want: true},
+		{a: `C:\Zed\zed.exe`, b: `c:\zed\ZED.EXE`, want: true},
+		{a: `\\?\C:\Zed\zed.exe`, b: `C:\Zed\zed.exe`, want: true},
+		{a: `\\?\UNC\server\tools\zed.exe`, b: `\\server\tools\zed.exe`, want: true},
+		{a: `C:\Zed\zed.exe`, b: `C:\Zed Preview\zed.exe`, want: false},
+		{a: `C:\Zed\zed.exe`, b: `C:\Zed\zed-preview.exe`, want: false},
+		{a: "", b: `C:\Zed\zed.exe`, want: false},
+		{a: "", b: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := sameExecutable(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameExecutable(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIsZedRunning(t *testing.T) {
+	dir := t.TempDir()
+	stable := filepath.Join(dir, "Zed", "zed.exe")
+	preview := filepath.Join(dir, "Zed Preview", "zed.exe")
+
+	tests := []struct {
+		name      string
+		processes []ProcessInfo
+		want      bool
+	}{
+		{"same path", []ProcessInfo{{PID: 1, ExePath: stable}}, true},
+		{"different case", []ProcessInfo{{PID: 1, ExePath: strings.ToUpper(stable)}}, true},
+		{"extended-length prefix", []ProcessInfo{{PID: 1, ExePath: `\\?\` + stable}}, true},
+		{"only preview running", []ProcessInfo{{PID: 1, ExePath: preview}}, false},
+		{"among others", []ProcessInfo{{PID: 1, ExePath: preview}, {PID: 2, ExePath: ""}, {PID: 3, ExePath: stable}}, true},
+		{"path not readable", []ProcessInfo{{PID: 1, ExePath: ""}}, false},
+		{"nothing running", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lister := &fakeLister{processes: tt.processes}
+
+			got, err := isZedRunning(lister, stable)
+			if err != nil || got != tt.want {
+				t.Errorf("isZedRunning() = %v, %v; want %v, nil", got, err, tt.want)
+			}
+
+			if lister.asked != "zed.exe" {
+				t.Errorf("List() asked for %q, want zed.exe", lister.asked)
+			}
+		})
+	}
+
+	lister := &fakeLister{err: errors.New("access denied")}
+	if got, err := isZedRunning(lister, stable); got || err == nil {
+		t.Errorf("isZedRunning() = %v, %v; want false and the lister's error", got, err)
+	}
+}
+
+// procfs builds a fake procfs below a temp folder, with an exe link per pid
+func procfs(t *testing.T, exes map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for pid, exe := range exes {
+		dir := filepath.Join(root, pid)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if exe == "" {
+			continue
+		}
+
+		if err := os.Symlink(exe, filepath.Join(dir, "exe")); err != nil {
+			t.Skipf("symlinks not available: %v", err)
+		}
+	}
+
+	return root
+}
+
+func TestProcFSLister(t *testing.T) {
+	root := procfs(t, map[string]string{
+		"101":  "/opt/zed/zed.exe",
+		"102":  "/opt/zed-preview/ZED.EXE",
+		"103":  "/usr/bin/bash",
+		"104":  "",
+		"self": "/opt/zed/zed.exe",
+	})
+
+	tests := []struct {
+		name string
+		want []int
+	}{
+		{name: "", want: []int{101, 102, 103}},
+		{name: "zed.exe", want: []int{101, 102}},
+		{name: "code.exe", want: []int{}},
+	}
+
+	for _, tt := range tests {
+		processes, err := ProcFSLister{Root: root}.List(tt.name)
+		if err != nil {
+			t.Fatalf("List(%q) = %v", tt.name, err)
+		}
+
+		pids := []int{}
+		for _, proc := range processes {
+			pids = append(pids, proc.PID)
+		}
+
+		slices.Sort(pids)
+		if !slices.Equal(pids, tt.want) {
+			t.Errorf("List(%q) pids = %v, want %v", tt.name, pids, tt.want)
+		}
+	}
+
+	if _, err := (ProcFSLister{Root: filepath.Join(root, "missing")}).List(""); err == nil {
+		t.Error("List() on a missing procfs = nil, want an error")
+	}
+}
@@ -0,0 +1,67 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// defaultLister is the process lister used by LaunchZed
+var defaultLister Lister = windowsLister{}
+
+// windowsLister lists processes through a Toolhelp snapshot, without spawning PowerShell
+type windowsLister struct{}
+
+// List walks a process snapshot and queries the full image path of each process with a matching name
+func (windowsLister) List(name string) ([]ProcessInfo, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list processes: %w", err)
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	// One buffer for all the image paths, MAX_LONG_PATH characters is too much to allocate per process.
+	buffer := make([]uint16, windows.MAX_LONG_PATH)
+
+	processes := []ProcessInfo{}
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		// The snapshot already has the file name, opening the process is only worth it for a match.
+		if name != "" && !strings.EqualFold(windows.UTF16ToString(entry.ExeFile[:]), name) {
+			continue
+		}
+
+		processes = append(processes, ProcessInfo{
+			PID:     int(entry.ProcessID),
+			ExePath: imagePath(entry.ProcessID, buffer),
+		})
+	}
+
+	if !errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+		return nil, fmt.Errorf("unable to list processes: %w", err)
+	}
+
+	return processes, nil
+}
+
+// imagePath returns the full executable path of a process, or an empty string when it can't be queried.
+// The path is read into buffer, which is reused between calls.
+func imagePath(pid uint32, buffer []uint16) string {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(handle)
+
+	size := uint32(len(buffer))
+	if err := windows.QueryFullProcessImageName(handle, 0, &buffer[0], &size); err != nil {
+		return ""
+	}
+
+	return windows.UTF16ToString(buffer[:size])
+}
@@ -0,0 +1,80 @@
+package process
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// locationPattern matches `path:line`, `path:line:col` and `path:start-end` suffixes.
+// The path part is lazy so the suffix is taken from the right, which keeps drive letters (C:\) intact.
+var locationPattern = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+)|-(\d+))?:?$`)
+
+// drivePattern matches a bare drive letter, eg: the `C` in `C:12`
+var drivePattern = regexp.MustCompile(`^[A-Za-z]$`)
+
+// Location is a launch target with an optional position to jump to
+type Location struct {
+	// Path is the file or folder without the position suffix
+	Path string
+	// Line is the 1-based line to jump to, 0 when not set
+	Line int
+	// Column is the 1-based column to jump to, 0 when not set
+	Column int
+	// EndLine is the last line of a `path:start-end` range, 0 when not set
+	EndLine int
+}
+
+// ParseLocation splits a `path:line`, `path:line:col` or `path:start-end` argument into its parts.
+// Arguments without a position suffix are returned with only Path set. Lines and columns are 1-based,
+// so a suffix with a 0 in it (eg: `notes:0`) isn't a position and stays part of the path.
+func ParseLocation(arg string) Location {
+	match := locationPattern.FindStringSubmatch(arg)
+	if match == nil || drivePattern.MatchString(match[1]) {
+		return Location{Path: arg}
+	}
+
+	location := Location{Path: match[1]}
+	location.Line, _ = strconv.Atoi(match[2])
+
+	if match[3] != "" {
+		location.Column, _ = strconv.Atoi(match[3])
+	}
+
+	if match[4] != "" {
+		location.EndLine, _ = strconv.Atoi(match[4])
+	}
+
+	if location.Line == 0 || (match[3] != "" && location.Column == 0) || (match[4] != "" && location.EndLine == 0) {
+		return Location{Path: arg}
+	}
+
+	return location
+}
+
+// Validate checks that a line range doesn't end before it starts, eg: `main.go:5-3`
+func (l Location) Validate() error {
+	if l.EndLine > 0 && l.EndLine < l.Line {
+		return fmt.Errorf("line range %d-%d ends before it starts", l.Line, l.EndLine)
+	}
+
+	return nil
+}
+
+// HasPosition reports whether the location points at a line inside the file
+func (l Location) HasPosition() bool {
+	return l.Line > 0
+}
+
+// ZedArg formats the location the way Zed expects it on its command line.
+// Zed has no notion of line ranges, so `path:start-end` opens at the start line.
+func (l Location) ZedArg(path string) string {
+	switch {
+	case l.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", path, l.Line, l.Column)
+	case l.Line > 0:
+		return fmt.Sprintf("%s:%d", path, l.Line)
+	default:
+		return path
+	}
+}
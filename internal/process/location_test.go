@@ -0,0 +1,49 @@
+package process
+
+import "testing"
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want Location
+	}{
+		{arg: "main.go", want: Location{Path: "main.go"}},
+		{arg: "main.go:12", want: Location{Path: "main.go", Line: 12}},
+		{arg: "main.go:12:4", want: Location{Path: "main.go", Line: 12, Column: 4}},
+		{arg: "main.go:12:", want: Location{Path: "main.go", Line: 12}},
+		{arg: "main.go:5-9", want: Location{Path: "main.go", Line: 5, EndLine: 9}},
+		{arg: `C:\src\main.go:12:4`, want: Location{Path: `C:\src\main.go`, Line: 12, Column: 4}},
+		{arg: "C:12", want: Location{Path: "C:12"}},
+		{arg: "foo:0", want: Location{Path: "foo:0"}},
+		{arg: "foo:0:3", want: Location{Path: "foo:0:3"}},
+		{arg: "foo:3:0", want: Location{Path: "foo:3:0"}},
+		{arg: "foo:0-3", want: Location{Path: "foo:0-3"}},
+		{arg: "foo:3-0", want: Location{Path: "foo:3-0"}},
+		{arg: "a.go:5-3", want: Location{Path: "a.go", Line: 5, EndLine: 3}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseLocation(tt.arg); got != tt.want {
+			t.Errorf("ParseLocation(%q) = %+v, want %+v", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestLocationValidate(t *testing.T) {
+	tests := []struct {
+		arg     string
+		wantErr bool
+	}{
+		{arg: "main.go"},
+		{arg: "main.go:12"},
+		{arg: "main.go:5-9"},
+		{arg: "main.go:5-5"},
+		{arg: "main.go:5-3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ParseLocation(tt.arg).Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("ParseLocation(%q).Validate() = %v, want error %v", tt.arg, err, tt.wantErr)
+		}
+	}
+}
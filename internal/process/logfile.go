@@ -0,0 +1,56 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"zed-cli-win-unofficial/internal/utils"
+)
+
+const (
+	// maxLogSize is the size after which the Zed log is rotated
+	maxLogSize int64 = 5 * 1024 * 1024
+	// maxLogBackups is how many rotated logs (zed.log.1, zed.log.2, ...) are kept
+	maxLogBackups int = 3
+)
+
+// openLogFile opens the Zed log for appending, rotating it first when it grew past maxLogSize
+func openLogFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("unable to create log directory: %w", err)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() >= maxLogSize {
+		rotateLogFile(path)
+	}
+
+	file, err := openAppend(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open log file: %w", err)
+	}
+
+	return file, nil
+}
+
+// rotateLogFile shifts zed.log to zed.log.1, zed.log.1 to zed.log.2 and so on, dropping the oldest.
+// Failures don't stop the launch, a log that can't be rotated just keeps growing.
+func rotateLogFile(path string) {
+	oldest := fmt.Sprintf("%s.%d", path, maxLogBackups)
+	if err := os.Remove(oldest); err != nil && !errors.Is(err, os.ErrNotExist) {
+		utils.Debugln(fmt.Sprintf("Could not remove %s: %v", oldest, err))
+	}
+
+	for i := maxLogBackups - 1; i >= 1; i-- {
+		renameLogFile(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+	}
+
+	renameLogFile(path, path+".1")
+}
+
+// renameLogFile moves a log during rotation, a missing one is skipped
+func renameLogFile(from string, to string) {
+	if err := os.Rename(from, to); err != nil && !errors.Is(err, os.ErrNotExist) {
+		utils.Debugln(fmt.Sprintf("Could not rotate %s: %v", from, err))
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package process
+
+import "os"
+
+// openAppend opens the log for appending, other systems let open files be renamed anyway
+func openAppend(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+}
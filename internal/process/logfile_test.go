@@ -0,0 +1,94 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readLog returns the content of a log, empty when it doesn't exist
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestOpenLogFileRotatesWhileOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "zed.log")
+
+	// The first launch's Zed keeps its log open while the next launch rotates it.
+	zedLog, err := openLogFile(path)
+	if err != nil {
+		t.Fatalf("openLogFile() = %v", err)
+	}
+	defer zedLog.Close()
+
+	if _, err := zedLog.WriteString("first run\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Truncate(path, maxLogSize); err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := openLogFile(path)
+	if err != nil {
+		t.Fatalf("openLogFile() while the log is open = %v", err)
+	}
+	defer next.Close()
+
+	if _, err := next.WriteString("second run\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readLog(t, path); got != "second run\n" {
+		t.Errorf("zed.log = %q, want only the second run", got)
+	}
+
+	info, err := os.Stat(path + ".1")
+	if err != nil || info.Size() != maxLogSize {
+		t.Errorf("zed.log.1 = %v, %v; want the rotated %d bytes", info, err, maxLogSize)
+	}
+
+	// The old Zed keeps writing into the file that was rotated away.
+	if _, err := zedLog.WriteString("still running\n"); err != nil {
+		t.Errorf("write after rotation = %v", err)
+	}
+
+	if got := readLog(t, path); got != "second run\n" {
+		t.Errorf("zed.log = %q after the old Zed wrote, want only the second run", got)
+	}
+}
+
+func TestRotateLogFileKeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zed.log")
+
+	for i := 0; i <= maxLogBackups; i++ {
+		name := path
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", path, i)
+		}
+
+		if err := os.WriteFile(name, []byte(fmt.Sprint(i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rotateLogFile(path)
+
+	if got := readLog(t, path); got != "" {
+		t.Errorf("zed.log = %q, want it moved away", got)
+	}
+
+	for i := 1; i <= maxLogBackups; i++ {
+		if got, want := readLog(t, fmt.Sprintf("%s.%d", path, i)), fmt.Sprint(i-1); got != want {
+			t.Errorf("zed.log.%d = %q, want %q", i, got, want)
+		}
+	}
+}
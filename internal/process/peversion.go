@@ -0,0 +1,121 @@
+package process
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// rtVersion is the resource type ID of VS_VERSIONINFO
+	rtVersion = 16
+	// fixedFileInfoSignature starts the VS_FIXEDFILEINFO structure
+	fixedFileInfoSignature = 0xFEEF04BD
+	// resourceSubdirectory flags a resource directory entry that points at another directory
+	resourceSubdirectory = 0x80000000
+)
+
+var errNoVersionResource = errors.New("no version resource")
+
+// ReadPEProductVersion reads the fixed product version (eg: 0.190.5.0) from the version resource of a PE file.
+// It parses the file directly, so it works on any platform.
+func ReadPEProductVersion(r io.ReaderAt) (string, error) {
+	file, err := pe.NewFile(r)
+	if err != nil {
+		return "", fmt.Errorf("not a PE file: %w", err)
+	}
+	defer file.Close()
+
+	section := file.Section(".rsrc")
+	if section == nil {
+		return "", errNoVersionResource
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		return "", fmt.Errorf("unable to read resources: %w", err)
+	}
+
+	rva, size, err := findVersionResource(data)
+	if err != nil {
+		return "", err
+	}
+
+	start := int64(rva) - int64(section.VirtualAddress)
+	if start < 0 || start+int64(size) > int64(len(data)) {
+		return "", fmt.Errorf("version resource lies outside the resource section")
+	}
+
+	return parseFixedFileInfo(data[start : start+int64(size)])
+}
+
+// findVersionResource walks the type -> name -> language levels of the resource tree down to the RT_VERSION data
+func findVersionResource(data []byte) (uint32, uint32, error) {
+	offset, err := findResourceEntry(data, 0, rtVersion)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The name and language levels: the first entry is the one Windows uses as well.
+	for level := 0; level < 2; level++ {
+		if offset&resourceSubdirectory == 0 {
+			return 0, 0, errNoVersionResource
+		}
+
+		if offset, err = findResourceEntry(data, offset&^resourceSubdirectory, -1); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if offset&resourceSubdirectory != 0 || int(offset)+8 > len(data) {
+		return 0, 0, errNoVersionResource
+	}
+
+	rva := binary.LittleEndian.Uint32(data[offset:])
+	size := binary.LittleEndian.Uint32(data[offset+4:])
+	return rva, size, nil
+}
+
+// findResourceEntry returns the OffsetToData of the entry with the given ID in the directory at dirOffset.
+// An ID of -1 picks the first entry.
+func findResourceEntry(data []byte, dirOffset uint32, id int) (uint32, error) {
+	if int(dirOffset)+16 > len(data) {
+		return 0, errNoVersionResource
+	}
+
+	named := binary.LittleEndian.Uint16(data[dirOffset+12:])
+	ids := binary.LittleEndian.Uint16(data[dirOffset+14:])
+
+	for i := 0; i < int(named)+int(ids); i++ {
+		entry := int(dirOffset) + 16 + i*8
+		if entry+8 > len(data) {
+			break
+		}
+
+		name := binary.LittleEndian.Uint32(data[entry:])
+		if id == -1 || (name&resourceSubdirectory == 0 && int(name) == id) {
+			return binary.LittleEndian.Uint32(data[entry+4:]), nil
+		}
+	}
+
+	return 0, errNoVersionResource
+}
+
+// parseFixedFileInfo finds VS_FIXEDFILEINFO inside a VS_VERSIONINFO blob and formats its product version
+func parseFixedFileInfo(info []byte) (string, error) {
+	signature := binary.LittleEndian.AppendUint32(nil, fixedFileInfoSignature)
+
+	index := bytes.Index(info, signature)
+	if index < 0 || index+24 > len(info) {
+		return "", fmt.Errorf("version resource has no fixed file info")
+	}
+
+	// Signature, struct version, file version (MS, LS), then product version (MS, LS).
+	productMS := binary.LittleEndian.Uint32(info[index+16:])
+	productLS := binary.LittleEndian.Uint32(info[index+20:])
+
+	return fmt.Sprintf("%d.%d.%d.%d", productMS>>16, productMS&0xFFFF, productLS>>16, productLS&0xFFFF), nil
+}
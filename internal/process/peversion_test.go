@@ -0,0 +1,51 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The sample executables in testdata are minimal PE32+ images: zed-0.190.5.exe and zed-0.0.0.exe
+// only hold a .rsrc section with a VS_VERSIONINFO resource, no-resources.exe has just a .text section.
+
+func TestReadPEProductVersion(t *testing.T) {
+	tests := []struct {
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{file: "zed-0.190.5.exe", want: "0.190.5.0"},
+		{file: "zed-0.0.0.exe", want: "0.0.0.0"},
+		{file: "no-resources.exe", wantErr: true},
+		{file: "not-pe.exe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			file, err := os.Open(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			got, err := ReadPEProductVersion(file)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ReadPEProductVersion() = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadPEProductVersionWithoutResources(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "no-resources.exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := ReadPEProductVersion(file); !errors.Is(err, errNoVersionResource) {
+		t.Errorf("err = %v, want %v", err, errNoVersionResource)
+	}
+}
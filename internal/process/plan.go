@@ -0,0 +1,63 @@
+package process
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"zed-cli-win-unofficial/internal/environ"
+)
+
+// Plan is what a launch would do, collected instead of acted on with LaunchOptions.DryRun
+type Plan struct {
+	// Script is the setup script that would be run for the environment, it isn't run to find out what it sets
+	Script string
+	// Executable is the zed.exe that would be started
+	Executable string
+	// Args are the arguments zed.exe would get
+	Args []string
+	// Dir is the working directory Zed would start in
+	Dir string
+	// Env holds the `KEY=VALUE` assignments that differ from the CLI's own environment, sorted by key
+	Env []string
+	// Creations lists the files and folders that would be created, eg: "folder C:\code\new"
+	Creations []string
+}
+
+// addCreation records a file or folder the launch would create
+func (p *Plan) addCreation(kind string, path string) {
+	p.Creations = append(p.Creations, kind+" "+path)
+}
+
+// Write prints the plan one item per line, in a fixed order and format so it can be compared in scripts
+func (p *Plan) Write(w io.Writer) {
+	if p.Script != "" {
+		fmt.Fprintf(w, "SCRIPT %s\n", p.Script)
+	}
+
+	for _, creation := range p.Creations {
+		fmt.Fprintf(w, "CREATE %s\n", creation)
+	}
+
+	fmt.Fprintf(w, "EXEC   %s\n", p.Executable)
+	for _, arg := range p.Args {
+		fmt.Fprintf(w, "ARG    %s\n", arg)
+	}
+
+	fmt.Fprintf(w, "DIR    %s\n", p.Dir)
+	for _, assignment := range p.Env {
+		fmt.Fprintf(w, "ENV    %s\n", assignment)
+	}
+}
+
+// complete fills in the parts of the plan that are known once the final command line is
+func (p *Plan) complete(zedPath string, args []string, env []string) {
+	p.Executable = zedPath
+	p.Args = args
+	p.Dir, _ = os.Getwd()
+
+	if len(env) > 0 {
+		p.Env = environ.Diff(os.Environ(), environ.Merge(os.Environ(), env))
+		slices.Sort(p.Env)
+	}
+}
@@ -0,0 +1,96 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeEnvScript writes a setup script that creates marker and sets ZED_TEST_SCRIPT=1
+func writeEnvScript(t *testing.T, marker string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "setup.sh")
+	content := "echo ran > \"" + marker + "\"\nexport ZED_TEST_SCRIPT=1\n"
+
+	if runtime.GOOS == "windows" {
+		script = filepath.Join(dir, "setup.bat")
+		content = "@echo ran> \"" + marker + "\"\r\n@set ZED_TEST_SCRIPT=1\r\n"
+	}
+
+	if err := os.WriteFile(script, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return script
+}
+
+func TestApplyEnvScriptSkipsScriptInDryRun(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	script := writeEnvScript(t, marker)
+
+	opts := LaunchOptions{EnvScript: script, Env: []string{"A=1"}, plan: &Plan{}}
+	if err := applyEnvScript(&opts); err != nil {
+		t.Fatalf("applyEnvScript() = %v", err)
+	}
+
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("script ran during a dry run (stat = %v)", err)
+	}
+
+	if opts.plan.Script != script {
+		t.Errorf("plan.Script = %q, want %q", opts.plan.Script, script)
+	}
+
+	if len(opts.Env) != 1 || opts.Env[0] != "A=1" {
+		t.Errorf("Env = %q, want [A=1]", opts.Env)
+	}
+}
+
+func TestApplyEnvScriptRunsScript(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	script := writeEnvScript(t, marker)
+
+	opts := LaunchOptions{EnvScript: script, Env: []string{"A=1"}}
+	if err := applyEnvScript(&opts); err != nil {
+		t.Fatalf("applyEnvScript() = %v", err)
+	}
+
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("script didn't run: %v", err)
+	}
+
+	// The script's variables come first, so the ones given on the command line win.
+	if !slices.Contains(opts.Env, "ZED_TEST_SCRIPT=1") || opts.Env[len(opts.Env)-1] != "A=1" {
+		t.Errorf("Env = %q, want ZED_TEST_SCRIPT=1 followed by A=1", opts.Env)
+	}
+}
+
+func TestPlanWrite(t *testing.T) {
+	plan := Plan{
+		Script:     `C:\tools\setup.bat`,
+		Executable: `C:\Zed\zed.exe`,
+		Args:       []string{`C:\code\api`},
+		Dir:        `C:\code`,
+		Env:        []string{"A=1"},
+		Creations:  []string{`folder C:\code\api`},
+	}
+
+	var out strings.Builder
+	plan.Write(&out)
+
+	want := "SCRIPT C:\\tools\\setup.bat\n" +
+		"CREATE folder C:\\code\\api\n" +
+		"EXEC   C:\\Zed\\zed.exe\n" +
+		"ARG    C:\\code\\api\n" +
+		"DIR    C:\\code\n" +
+		"ENV    A=1\n"
+
+	if out.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,44 @@
+package process
+
+import (
+	"fmt"
+	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/pathutil"
+	"zed-cli-win-unofficial/internal/utils"
+)
+
+// TranslatePaths turns the Git Bash, Cygwin and WSL style launch targets into Windows paths, keeping any
+// `:line:col` suffix. It runs before anything else looks at the targets, so globs, bookmarks, workspaces
+// and project roots all get Windows paths.
+func TranslatePaths(projectPaths []string, wslDistro string) []string {
+	translated := make([]string, 0, len(projectPaths))
+
+	for _, projectPath := range projectPaths {
+		location := ParseLocation(projectPath)
+
+		// A file literally named like `notes:1` takes precedence over the position syntax.
+		if location.HasPosition() && config.FileExists(projectPath) {
+			location = Location{Path: projectPath}
+		}
+
+		translated = append(translated, translatePath(location.Path, wslDistro)+projectPath[len(location.Path):])
+	}
+
+	return translated
+}
+
+// translatePath turns a Git Bash, Cygwin or WSL style path into a Windows path.
+// A path that exists as given is left alone, so a real `C:\c` folder still wins over `/c`.
+func translatePath(path string, wslDistro string) string {
+	if config.FileExists(path) {
+		return path
+	}
+
+	converted, ok := pathutil.FromPOSIX(path, wslDistro)
+	if !ok {
+		return path
+	}
+
+	utils.Debugln(fmt.Sprintf("Translated %s to %s", path, converted))
+	return converted
+}
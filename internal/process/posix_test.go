@@ -0,0 +1,33 @@
+package process
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTranslatePaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		paths  []string
+		distro string
+		want   []string
+	}{
+		{"git bash", []string{"/c/code/api"}, "", []string{`C:\code\api`}},
+		{"position suffix", []string{"/c/code/main.go:12:5"}, "", []string{`C:\code\main.go:12:5`}},
+		{"line range", []string{"/mnt/d/work/main.go:3-7"}, "", []string{`D:\work\main.go:3-7`}},
+		{"wsl distro", []string{"/home/me/src:4"}, "Ubuntu", []string{`\\wsl.localhost\Ubuntu\home\me\src:4`}},
+		{"distro unknown", []string{"/home/me/src"}, "", []string{"/home/me/src"}},
+		{"glob", []string{"/c/code/*.go"}, "", []string{`C:\code\*.go`}},
+		{"windows path", []string{`C:\code\api:3`}, "", []string{`C:\code\api:3`}},
+		{"stdin and bookmarks", []string{"-", "@api"}, "", []string{"-", "@api"}},
+		{"mixed", []string{"/cygdrive/e/x", `D:\y`}, "", []string{`E:\x`, `D:\y`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TranslatePaths(tt.paths, tt.distro); !slices.Equal(got, tt.want) {
+				t.Errorf("TranslatePaths(%q) = %q, want %q", tt.paths, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package process
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"zed-cli-win-unofficial/internal/fileext"
+	"zed-cli-win-unofficial/internal/utils"
+)
+
+// StdinArg is the launch argument that stands for the CLI's standard input
+const StdinArg string = "-"
+
+// stdinTempName is the name pattern of the temp files stdin is captured into
+const stdinTempName string = "zed-stdin-*"
+
+// stdinTempPattern is where stdin would be captured, the `*` standing for the random part of the name
+func stdinTempPattern() string {
+	return filepath.Join(os.TempDir(), stdinTempName)
+}
+
+// StdinIsPiped checks if data is being piped into the CLI, eg: `git diff | zed`
+func StdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeNamedPipe == 0 {
+		return false
+	}
+
+	// mintty (Git Bash, Cygwin, MSYS2) hands programs a pipe instead of a console, that's still someone typing.
+	return !isPtyPipe(stdinPipeName())
+}
+
+// isPtyPipe checks if a pipe name is the terminal of a Cygwin or MSYS pty, eg: \msys-1888ae32e00d56aa-pty0-from-master
+func isPtyPipe(name string) bool {
+	name = strings.TrimPrefix(name, `\Device\NamedPipe`)
+
+	token := strings.Split(name, "-")
+	if len(token) < 5 {
+		return false
+	}
+
+	return (token[0] == `\msys` || token[0] == `\cygwin`) &&
+		token[1] != "" &&
+		strings.HasPrefix(token[2], "pty") &&
+		(token[3] == "from" || token[3] == "to") &&
+		token[4] == "master"
+}
+
+// CaptureStdin copies everything read from r into a temp file, named with an extension guessed from its content
+func CaptureStdin(r io.Reader) (string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("unable to read from stdin: %w", err)
+	}
+
+	file, err := os.CreateTemp("", stdinTempName+fileext.FromContent(content))
+	if err != nil {
+		return "", fmt.Errorf("unable to create temp file: %w", err)
+	}
+
+	defer file.Close()
+
+	if _, err := file.Write(content); err != nil {
+		return "", fmt.Errorf("unable to write temp file: %w", err)
+	}
+
+	utils.Debug("Captured %d bytes from stdin into: %s\n", len(content), file.Name())
+	return file.Name(), nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package process
+
+// stdinPipeName returns the name of the pipe stdin reads from, pipes have no names to tell them apart here
+func stdinPipeName() string {
+	return ""
+}
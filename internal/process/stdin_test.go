@@ -0,0 +1,29 @@
+package process
+
+import "testing"
+
+func TestIsPtyPipe(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: `\msys-1888ae32e00d56aa-pty0-from-master`, want: true},
+		{name: `\cygwin-e022582115c10879-pty4-from-master`, want: true},
+		{name: `\msys-1888ae32e00d56aa-pty0-to-master`, want: true},
+		{name: `\Device\NamedPipe\msys-1888ae32e00d56aa-pty1-from-master`, want: true},
+		{name: `\msys-1888ae32e00d56aa-pty0-from-master-nat`, want: true},
+		{name: ``, want: false},
+		{name: `\msys-1888ae32e00d56aa-pipe-0x12-from-master`, want: false},
+		{name: `\msys--pty0-from-master`, want: false},
+		{name: `\msys-1888ae32e00d56aa-pty0-from-slave`, want: false},
+		{name: `\msys-1888ae32e00d56aa-pty0-master`, want: false},
+		{name: `\Winsock2\CatalogChangeListener-1a4-0`, want: false},
+		{name: `\mintty-1888ae32e00d56aa-pty0-from-master`, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isPtyPipe(tt.name); got != tt.want {
+			t.Errorf("isPtyPipe(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package process
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// stdinPipeName returns the name of the pipe stdin reads from, empty when it isn't a pipe
+func stdinPipeName() string {
+	handle := windows.Handle(os.Stdin.Fd())
+	if fileType, err := windows.GetFileType(handle); err != nil || fileType != windows.FILE_TYPE_PIPE {
+		return ""
+	}
+
+	// FILE_NAME_INFO: the length of the name in bytes, followed by the UTF-16 name itself
+	buf := make([]uint16, 2+windows.MAX_PATH)
+	if err := windows.GetFileInformationByHandleEx(handle, windows.FileNameInfo, (*byte)(unsafe.Pointer(&buf[0])), uint32(len(buf)*2)); err != nil {
+		return ""
+	}
+
+	length := int(uint32(buf[0])|uint32(buf[1])<<16) / 2
+	if length > len(buf)-2 {
+		length = len(buf) - 2
+	}
+
+	return windows.UTF16ToString(buf[2 : 2+length])
+}
@@ -0,0 +1,175 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"zed-cli-win-unofficial/internal/utils"
+
+	"github.com/hashicorp/go-version"
+)
+
+// VersionSource names the strategy that produced a detected Zed version
+type VersionSource string
+
+const (
+	SourceOverride    VersionSource = "config override"
+	SourcePEResource  VersionSource = "PE version resource"
+	SourceCommand     VersionSource = "zed --version"
+	SourceVersionFile VersionSource = "version file"
+)
+
+// versionCommandTimeout bounds `zed.exe --version`, in case the build opens a window instead of printing
+const versionCommandTimeout = 5 * time.Second
+
+// versionFileNames are the files next to zed.exe that may hold its version
+var versionFileNames = []string{"version.txt", "VERSION", "version"}
+
+// versionPattern finds a dotted version number in free-form text, eg: `Zed 0.190.5 8e9f2d1`
+var versionPattern = regexp.MustCompile(`\d+\.\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.\-]+)?`)
+
+// DetectedVersion is a Zed version together with the strategy that found it
+type DetectedVersion struct {
+	Version *version.Version
+	Source  VersionSource
+}
+
+// versionStrategy is a single way of finding the Zed version, returning the raw version text
+type versionStrategy struct {
+	source VersionSource
+	detect func(zedPath string) (string, error)
+}
+
+var versionStrategies = []versionStrategy{
+	{source: SourcePEResource, detect: readPEVersionFile},
+	{source: SourceCommand, detect: runVersionCommand},
+	{source: SourceVersionFile, detect: readVersionFile},
+}
+
+// DetectZedVersion tries the override from the config first, then every detection strategy in order.
+// The first strategy that yields a usable version wins.
+func DetectZedVersion(zedPath string, override string) (*DetectedVersion, error) {
+	return detectVersion(zedPath, override, versionStrategies)
+}
+
+// readOnlyStrategies are the strategies that only read files, the ones a dry run may use
+func readOnlyStrategies() []versionStrategy {
+	strategies := []versionStrategy{}
+	for _, strategy := range versionStrategies {
+		if strategy.source != SourceCommand {
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	return strategies
+}
+
+// detectVersion is DetectZedVersion limited to the given strategies
+func detectVersion(zedPath string, override string, strategies []versionStrategy) (*DetectedVersion, error) {
+	if override != "" {
+		v, err := ParseZedVersion(override)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version override: %w", err)
+		}
+
+		return &DetectedVersion{Version: v, Source: SourceOverride}, nil
+	}
+
+	failures := []error{}
+	for _, strategy := range strategies {
+		raw, err := strategy.detect(zedPath)
+		if err == nil {
+			var v *version.Version
+			if v, err = ParseZedVersion(raw); err == nil {
+				utils.Debug("Zed version %s detected from %s\n", v.String(), strategy.source)
+				return &DetectedVersion{Version: v, Source: strategy.source}, nil
+			}
+		}
+
+		failures = append(failures, fmt.Errorf("%s: %w", strategy.source, err))
+	}
+
+	return nil, fmt.Errorf("could not detect version of %s: %w", zedPath, errors.Join(failures...))
+}
+
+// GetZedVersion retrieves the version of the Zed executable
+func GetZedVersion(zedPath string) (*version.Version, error) {
+	detected, err := DetectZedVersion(zedPath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return detected.Version, nil
+}
+
+// ParseZedVersion parses a version string, rejecting the all-zero placeholder some local builds carry
+func ParseZedVersion(raw string) (*version.Version, error) {
+	v, err := version.NewVersion(strings.TrimPrefix(strings.TrimSpace(raw), "v"))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse version string: %w", err)
+	}
+
+	for _, segment := range v.Segments() {
+		if segment != 0 {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("bogus version: %s", raw)
+}
+
+// ParseVersionOutput extracts the version number from the output of `zed.exe --version`
+func ParseVersionOutput(output string) (string, error) {
+	match := versionPattern.FindString(output)
+	if match == "" {
+		return "", fmt.Errorf("no version found in output: %q", strings.TrimSpace(output))
+	}
+
+	return match, nil
+}
+
+// readPEVersionFile reads the product version from the PE resource of the executable
+func readPEVersionFile(zedPath string) (string, error) {
+	file, err := os.Open(zedPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return ReadPEProductVersion(file)
+}
+
+// runVersionCommand asks the executable itself, eg: `Zed 0.190.5 8e9f2d1`
+func runVersionCommand(zedPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionCommandTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, zedPath, "--version").Output()
+	if err != nil {
+		return "", err
+	}
+
+	return ParseVersionOutput(string(output))
+}
+
+// readVersionFile reads a version file that sits next to the executable
+func readVersionFile(zedPath string) (string, error) {
+	dir := filepath.Dir(zedPath)
+
+	for _, name := range versionFileNames {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+
+		return ParseVersionOutput(string(content))
+	}
+
+	return "", fmt.Errorf("no version file in %s", dir)
+}
@@ -0,0 +1,98 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseVersionOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "stable", output: "Zed 0.190.5 8e9f2d1\n", want: "0.190.5"},
+		{name: "preview", output: "Zed Preview 0.191.2-pre a1b2c3d\r\n", want: "0.191.2-pre"},
+		{name: "four segments", output: "zed 0.190.5.0", want: "0.190.5.0"},
+		{name: "bare", output: "0.177.0", want: "0.177.0"},
+		{name: "version file", output: "v0.201.3\n", want: "0.201.3"},
+		{name: "no version", output: "Zed dev build\n", wantErr: true},
+		{name: "empty", output: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersionOutput(tt.output)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseVersionOutput(%q) = %q, %v; want %q, error %v", tt.output, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseZedVersion(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: "0.190.5", want: "0.190.5"},
+		{raw: "v0.190.5", want: "0.190.5"},
+		{raw: " 0.190.5.0\n", want: "0.190.5.0"},
+		{raw: "0.191.2-pre", want: "0.191.2-pre"},
+		{raw: "0.0.0", wantErr: true},
+		{raw: "0.0.0.0", wantErr: true},
+		{raw: "dev", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			v, err := ParseZedVersion(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseZedVersion(%q) error = %v, want error %v", tt.raw, err, tt.wantErr)
+			}
+
+			if err == nil && v.String() != tt.want {
+				t.Errorf("ParseZedVersion(%q) = %s, want %s", tt.raw, v.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectZedVersion(t *testing.T) {
+	t.Run("override", func(t *testing.T) {
+		detected, err := DetectZedVersion(filepath.Join("testdata", "not-pe.exe"), "0.180.0")
+		if err != nil || detected.Version.String() != "0.180.0" || detected.Source != SourceOverride {
+			t.Errorf("DetectZedVersion() = %v, %v; want 0.180.0 from the override", detected, err)
+		}
+	})
+
+	t.Run("PE resource", func(t *testing.T) {
+		detected, err := DetectZedVersion(filepath.Join("testdata", "zed-0.190.5.exe"), "")
+		if err != nil || detected.Version.String() != "0.190.5.0" || detected.Source != SourcePEResource {
+			t.Errorf("DetectZedVersion() = %v, %v; want 0.190.5.0 from the PE resource", detected, err)
+		}
+	})
+
+	t.Run("version file", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "version.txt"), []byte("0.185.1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// The missing zed.exe makes both the PE and the --version strategies fail.
+		detected, err := DetectZedVersion(filepath.Join(dir, "zed.exe"), "")
+		if err != nil || detected.Version.String() != "0.185.1" || detected.Source != SourceVersionFile {
+			t.Errorf("DetectZedVersion() = %v, %v; want 0.185.1 from the version file", detected, err)
+		}
+	})
+
+	t.Run("nothing", func(t *testing.T) {
+		if detected, err := DetectZedVersion(filepath.Join(t.TempDir(), "zed.exe"), ""); err == nil {
+			t.Errorf("DetectZedVersion() = %v, want an error", detected)
+		}
+	})
+}
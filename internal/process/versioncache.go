@@ -0,0 +1,110 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"zed-cli-win-unofficial/internal/pathutil"
+	"zed-cli-win-unofficial/internal/utils"
+)
+
+// versionCacheEntry is the version detected for an executable, valid as long as the file is unchanged
+type versionCacheEntry struct {
+	Size    int64         `json:"size"`
+	ModTime time.Time     `json:"modTime"`
+	Version string        `json:"version"`
+	Source  VersionSource `json:"source"`
+}
+
+// detectCachedVersion is DetectZedVersion backed by the cache file at cachePath.
+// Detection can end up running `zed.exe --version`, so its result is reused until Zed is updated.
+// A read-only detection, used by dry runs, never runs zed.exe and leaves the cache file alone.
+func detectCachedVersion(zedPath string, override string, cachePath string, readOnly bool) (*DetectedVersion, error) {
+	strategies := versionStrategies
+	if readOnly {
+		strategies = readOnlyStrategies()
+	}
+
+	if override != "" || cachePath == "" {
+		return detectVersion(zedPath, override, strategies)
+	}
+
+	info, err := pathutil.Stat(zedPath)
+	if err != nil {
+		return detectVersion(zedPath, "", strategies)
+	}
+
+	key := strings.ToLower(pathutil.Clean(zedPath))
+	cache := loadVersionCache(cachePath)
+
+	if entry, ok := cache[key]; ok && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		if v, err := ParseZedVersion(entry.Version); err == nil {
+			utils.Debug("Zed version %s taken from the cache\n", v.String())
+			return &DetectedVersion{Version: v, Source: entry.Source}, nil
+		}
+	}
+
+	detected, err := detectVersion(zedPath, "", strategies)
+	if err != nil || readOnly {
+		return detected, err
+	}
+
+	cache[key] = versionCacheEntry{Size: info.Size(), ModTime: info.ModTime(), Version: detected.Version.String(), Source: detected.Source}
+	if err := saveVersionCache(cachePath, cache); err != nil {
+		utils.Debug("Could not cache the Zed version: %v\n", err)
+	}
+
+	return detected, nil
+}
+
+// loadVersionCache reads the cache file, a missing or broken one is an empty cache
+func loadVersionCache(cachePath string) map[string]versionCacheEntry {
+	cache := map[string]versionCacheEntry{}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return cache
+	}
+
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string]versionCacheEntry{}
+	}
+
+	return cache
+}
+
+// saveVersionCache writes the cache file through a temp file, so a concurrent launch never reads half of it
+func saveVersionCache(cachePath string, cache map[string]versionCacheEntry) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode version cache: %w", err)
+	}
+
+	if err := pathutil.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("unable to write version cache: %w", err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(cachePath), filepath.Base(cachePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to write version cache: %w", err)
+	}
+
+	_, writeErr := tempFile.Write(data)
+	closeErr := tempFile.Close()
+
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tempFile.Name())
+		return fmt.Errorf("unable to write version cache: %w", err)
+	}
+
+	if err := os.Rename(tempFile.Name(), cachePath); err != nil {
+		os.Remove(tempFile.Name())
+		return fmt.Errorf("unable to write version cache: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"zed-cli-win-unofficial/internal/pathutil"
+)
+
+func TestDetectCachedVersion(t *testing.T) {
+	dir := t.TempDir()
+	zedPath := filepath.Join(dir, "zed.exe")
+	cachePath := filepath.Join(dir, "cache", "version-cache.json")
+
+	sample, err := os.ReadFile(filepath.Join("testdata", "zed-0.190.5.exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(zedPath, sample, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	detected, err := detectCachedVersion(zedPath, "", cachePath, false)
+	if err != nil || detected.Version.String() != "0.190.5.0" {
+		t.Fatalf("detectCachedVersion() = %v, %v; want 0.190.5.0", detected, err)
+	}
+
+	// A cached version wins as long as the executable is unchanged, so edit the cache to tell them apart.
+	cache := loadVersionCache(cachePath)
+	key := strings.ToLower(pathutil.Clean(zedPath))
+	entry, ok := cache[key]
+	if !ok {
+		t.Fatalf("cache = %v, want an entry for %s", cache, key)
+	}
+
+	entry.Version = "0.200.0"
+	cache[key] = entry
+	if err := saveVersionCache(cachePath, cache); err != nil {
+		t.Fatal(err)
+	}
+
+	if detected, err := detectCachedVersion(zedPath, "", cachePath, false); err != nil || detected.Version.String() != "0.200.0" {
+		t.Errorf("detectCachedVersion() = %v, %v; want the cached 0.200.0", detected, err)
+	}
+
+	// Updating Zed changes the file, which makes the cached version stale.
+	if err := os.WriteFile(zedPath, append(sample, 0), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if detected, err := detectCachedVersion(zedPath, "", cachePath, false); err != nil || detected.Version.String() != "0.190.5.0" {
+		t.Errorf("detectCachedVersion() = %v, %v; want a fresh 0.190.5.0", detected, err)
+	}
+
+	if detected, err := detectCachedVersion(zedPath, "0.150.0", cachePath, false); err != nil || detected.Source != SourceOverride {
+		t.Errorf("detectCachedVersion() = %v, %v; want the override", detected, err)
+	}
+}
+
+func TestDetectCachedVersionReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	zedPath := filepath.Join(dir, "zed.exe")
+	cachePath := filepath.Join(dir, "version-cache.json")
+
+	sample, err := os.ReadFile(filepath.Join("testdata", "zed-0.190.5.exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(zedPath, sample, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if detected, err := detectCachedVersion(zedPath, "", cachePath, true); err != nil || detected.Version.String() != "0.190.5.0" {
+		t.Errorf("detectCachedVersion() = %v, %v; want 0.190.5.0", detected, err)
+	}
+
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("read-only detection wrote the cache file: %v", err)
+	}
+
+	for _, strategy := range readOnlyStrategies() {
+		if strategy.source == SourceCommand {
+			t.Errorf("read-only detection runs %s", SourceCommand)
+		}
+	}
+}
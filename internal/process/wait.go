@@ -0,0 +1,51 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"zed-cli-win-unofficial/internal/utils"
+)
+
+// ExitError reports that Zed exited with a non-zero exit code while the CLI was waiting on it
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("Zed exited with code %d", e.Code)
+}
+
+// waitForZed blocks until the started Zed process exits.
+// Zed shares the console while we wait, so Ctrl+C reaches it directly and it decides for itself how to
+// shut down, eg: asking about unsaved changes. We only keep the interrupt from ending the wait early.
+func waitForZed(cmd *exec.Cmd) error {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	for {
+		select {
+		case <-interrupts:
+			utils.Debugln("Interrupted, still waiting for Zed to close")
+		case err := <-done:
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return &ExitError{Code: exitErr.ExitCode()}
+			}
+
+			if err != nil {
+				return fmt.Errorf("unable to wait for Zed: %w", err)
+			}
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess is the stand-in for zed.exe: it sleeps for ZED_HELPER_SLEEP_MS and exits with ZED_HELPER_EXIT
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("ZED_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	sleep, _ := strconv.Atoi(os.Getenv("ZED_HELPER_SLEEP_MS"))
+	code, _ := strconv.Atoi(os.Getenv("ZED_HELPER_EXIT"))
+
+	time.Sleep(time.Duration(sleep) * time.Millisecond)
+	os.Exit(code)
+}
+
+// fakeZed starts the test binary as a stand-in Zed that exits with code after sleep
+func fakeZed(t *testing.T, code int, sleep time.Duration) *exec.Cmd {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(),
+		"ZED_HELPER_PROCESS=1",
+		"ZED_HELPER_EXIT="+strconv.Itoa(code),
+		"ZED_HELPER_SLEEP_MS="+strconv.Itoa(int(sleep.Milliseconds())),
+	)
+
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return cmd
+}
+
+func TestWaitForZed(t *testing.T) {
+	if err := waitForZed(fakeZed(t, 0, 0)); err != nil {
+		t.Errorf("waitForZed() = %v, want nil", err)
+	}
+
+	var exitErr *ExitError
+	if err := waitForZed(fakeZed(t, 3, 0)); !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Errorf("waitForZed() = %v, want exit code 3", err)
+	}
+}
+
+func TestWaitForZedKeepsZedRunningOnInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("a process can't send itself os.Interrupt on Windows")
+	}
+
+	cmd := fakeZed(t, 0, 300*time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- waitForZed(cmd)
+	}()
+
+	// Give waitForZed time to start listening, then interrupt the CLI only.
+	time.Sleep(50 * time.Millisecond)
+	self, _ := os.FindProcess(os.Getpid())
+	if err := self.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("waitForZed() = %v, want Zed to close on its own", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waitForZed() didn't return")
+	}
+}
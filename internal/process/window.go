@@ -0,0 +1,45 @@
+package process
+
+import (
+	"zed-cli-win-unofficial/internal/clierr"
+
+	"github.com/hashicorp/go-version"
+)
+
+// WindowMode controls which Zed window the given paths are opened in
+type WindowMode int
+
+const (
+	// WindowDefault lets Zed decide, which is what plain `zed <path>` has always done
+	WindowDefault WindowMode = iota
+	// WindowNew always opens the paths in a new window
+	WindowNew
+	// WindowAdd adds the paths to the workspace of the current window
+	WindowAdd
+	// WindowReuse replaces the workspace of the current window with the paths
+	WindowReuse
+)
+
+var windowFlags = map[WindowMode]zedFlag{
+	WindowNew:   {name: "--new", minVersion: "0.190.0"},
+	WindowAdd:   {name: "--add", minVersion: "0.190.0"},
+	WindowReuse: {name: "--reuse", minVersion: "0.197.0"},
+}
+
+// windowArgs translates the window mode to the Zed flags supported by the given Zed version
+func windowArgs(mode WindowMode, zedVersion *version.Version, hasPaths bool) ([]string, error) {
+	flag, ok := windowFlags[mode]
+	if !ok {
+		return nil, nil
+	}
+
+	if mode != WindowNew && !hasPaths {
+		return nil, clierr.New(clierr.Usage, "%s needs at least one path to open", flag.name)
+	}
+
+	if !flag.supportedBy(zedVersion) {
+		return nil, clierr.New(clierr.VersionTooOld, "%s requires Zed v%s or newer, but v%s is installed", flag.name, flag.minVersion, zedVersion.String())
+	}
+
+	return []string{flag.name}, nil
+}
@@ -1,57 +1,129 @@
 package process
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
+	"zed-cli-win-unofficial/internal/clierr"
+	"zed-cli-win-unofficial/internal/config"
+	"zed-cli-win-unofficial/internal/environ"
+	"zed-cli-win-unofficial/internal/history"
+	"zed-cli-win-unofficial/internal/pathutil"
 	"zed-cli-win-unofficial/internal/utils"
 
-	"github.com/bi-zone/go-fileversion"
 	"github.com/hashicorp/go-version"
 )
 
 const MIN_ZED_VERSION string = "0.177.0"
 
-// GetZedVersion retrieves the version of the Zed executable
-func GetZedVersion(zedPath string) (*version.Version, error) {
-	info, err := fileversion.New(zedPath)
-	if err != nil {
-		return nil, fmt.Errorf("could not get version info for %s: %w", zedPath, err)
+// resolveProjectPath resolves a launch target to the argument handed to Zed and the absolute path it points at.
+// Targets with a `:line:col` suffix must point at an existing file, other missing paths follow the create policy.
+func resolveProjectPath(ctx context.Context, projectPath string, opts LaunchOptions) (string, string, error) {
+	location := ParseLocation(projectPath)
+
+	// A file literally named like `notes:1` takes precedence over the position syntax.
+	if location.HasPosition() && config.FileExists(projectPath) {
+		location = Location{Path: projectPath}
+	}
+
+	if err := location.Validate(); err != nil {
+		return "", "", err
 	}
 
-	v, err := version.NewVersion(info.FixedInfo().ProductVersion.String())
+	// Zed gets the plain form of the path, extended-length prefixes are only for our own file access.
+	absPath, err := filepath.Abs(pathutil.Display(location.Path))
 	if err != nil {
-		return nil, fmt.Errorf("could not parse version string: %w", err)
+		return "", "", fmt.Errorf("unable to resolve path: %w", err)
 	}
-	return v, nil
+
+	info, err := pathutil.Probe(ctx, absPath)
+	switch {
+	case os.IsNotExist(err) && location.HasPosition():
+		return "", "", fmt.Errorf("file not found: %s", absPath)
+	case os.IsNotExist(err):
+		if err := createMissingPath(ctx, absPath, looksLikeFile(location.Path), opts); err != nil {
+			return "", "", err
+		}
+	case err != nil:
+		return "", "", fmt.Errorf("unable to access path: %w", err)
+	case info.IsDir() && location.HasPosition():
+		return "", "", fmt.Errorf("cannot jump to a line in a folder: %s", absPath)
+	}
+
+	return location.ZedArg(absPath), absPath, nil
 }
 
-// isZedRunning checks if the Zed is currently running.
-func isZedRunning() (bool, error) {
-	cmd := exec.Command("powershell", "-NoLogo", "-NoProfile", "-Command", "Get-Process Zed -ErrorAction SilentlyContinue")
-	output, err := cmd.Output()
+// LaunchOptions controls how LaunchZed starts the Zed process
+type LaunchOptions struct {
+	// Wait blocks until the launched Zed process exits and reports its exit code
+	Wait bool
+	// Window controls whether the paths open in a new window, the current workspace or replace it
+	Window WindowMode
+	// Diff opens the two given files side by side in Zed's diff view
+	Diff bool
+	// ExtraArgs are forwarded verbatim to zed.exe, ahead of the paths
+	ExtraArgs []string
+	// Create decides what happens with paths that don't exist yet
+	Create CreatePolicy
+	// CreateParents allows missing parent folders of a new path to be created too
+	CreateParents bool
+	// VersionOverride replaces Zed version detection, for builds that carry no usable version
+	VersionOverride string
+	// Env holds `KEY=VALUE` assignments merged over the CLI's own environment for zed.exe
+	Env []string
+	// EnvScript is a setup script (eg: VsDevCmd.bat) run before the launch, its variables go under Env
+	EnvScript string
+	// Attach keeps Zed attached to the terminal with its output printed there, instead of detaching it
+	Attach bool
+	// LogPath is the file Zed's output is written to when it's not attached
+	LogPath string
+	// HistoryPath is the file the opened projects are recorded in, empty to not record them
+	HistoryPath string
+	// VersionCachePath is the file detected Zed versions are kept in, empty to detect them on every launch
+	VersionCachePath string
+	// DryRun prints the launch plan to stdout instead of creating paths and starting Zed
+	DryRun bool
 
-	if err == nil && len(output) > 0 {
-		return true, nil
+	// plan collects what the launch would do during a dry run
+	plan *Plan
+	// handoff is set when Zed was already running, the started zed.exe then passes the paths on and exits
+	handoff bool
+}
+
+// LaunchZed launches zed with the given project paths (files and folders can be mixed).
+// Path checks give up when ctx is done or their deadline passes, so an offline share can't hang the launch.
+func LaunchZed(ctx context.Context, zedPath string, projectPaths []string, opts LaunchOptions) error {
+	if opts.DryRun {
+		opts.plan = &Plan{}
 	}
 
-	return false, nil
-}
+	if err := applyEnvScript(&opts); err != nil {
+		utils.Error(err.Error())
+		return clierr.Wrap(clierr.Usage, err)
+	}
+
+	isRunning, err := isZedRunning(defaultLister, zedPath)
+	if err != nil {
+		utils.Debugln(fmt.Sprintf("Could not check if Zed is running: %v", err))
+	}
 
-// LaunchZed launches zed with optional project path
-func LaunchZed(zedPath string, projectPath string) error {
-	var cmd *exec.Cmd
-	isRunning, _ := isZedRunning()
-	zedVersion, err := GetZedVersion(zedPath)
+	opts.handoff = isRunning
+
+	var zedVersion *version.Version
+	detected, err := detectCachedVersion(zedPath, opts.VersionOverride, opts.VersionCachePath, opts.DryRun)
 
 	if err != nil {
 		utils.Warning("Could not determine Zed version: " + err.Error())
 	} else {
-		utils.Debugln(fmt.Sprintf("Current Zed version: %s", zedVersion.String()))
+		zedVersion = detected.Version
+		utils.Debugln(fmt.Sprintf("Current Zed version: %s (from %s)", zedVersion.String(), detected.Source))
 	}
 
-	if isRunning {
+	// Without a version there's nothing to check against, so we let Zed handle it.
+	if isRunning && zedVersion != nil {
 		constraint, _ := version.NewConstraint("< " + MIN_ZED_VERSION)
 		utils.Debugln(fmt.Sprintf("Constraint Checking is %t\n", constraint.Check(zedVersion)))
 
@@ -63,33 +135,139 @@ func LaunchZed(zedPath string, projectPath string) error {
 			utils.Info("💡 Solutions:\n")
 			utils.Info("  1. Update Zed to the latest version (recommended)\n")
 			utils.Info("  2. Close the existing Zed window and try again\n")
-			return nil
+			return clierr.New(clierr.VersionTooOld, "Zed v%s is older than v%s", zedVersion.String(), MIN_ZED_VERSION)
 		}
 	}
 
-	if projectPath != "" {
-		if _, err := os.Stat(projectPath); os.IsNotExist(err) {
-			if err := os.MkdirAll(projectPath, 0755); err != nil {
-				return fmt.Errorf("unable to create project folder: %w", err)
-			}
+	args, err := windowArgs(opts.Window, zedVersion, len(projectPaths) > 0)
+	if err != nil {
+		utils.Error(err.Error())
+		return err
+	}
 
-			utils.Error("Path doesn't exists")
-			utils.Info("📁 Created new folder: %s\n", filepath.Clean(projectPath))
+	args = append(args, opts.ExtraArgs...)
+
+	if opts.Diff {
+		pathArgs, err := diffArgs(ctx, projectPaths, zedVersion)
+		if err != nil {
+			utils.Error(err.Error())
+			return err
 		}
 
-		cmd = exec.Command(zedPath, projectPath)
-	} else {
-		cmd = exec.Command(zedPath)
+		// Diffed files are mostly temp copies made by git, so they're kept out of the history.
+		return startZed(zedPath, append(args, pathArgs...), nil, opts)
 	}
 
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	// Each path is resolved on its own, so a single bad path doesn't stop the others from opening.
+	paths := make([]string, 0, len(projectPaths))
+	targets := make([]string, 0, len(projectPaths))
+	for _, projectPath := range projectPaths {
+		// A dry run leaves stdin unread, so there's only the temp file it would be captured into to report.
+		if projectPath == StdinArg && opts.plan != nil {
+			opts.plan.addCreation("file", stdinTempPattern())
+			paths = append(paths, stdinTempPattern())
+			continue
+		}
+
+		resolvedPath, absPath, err := resolveProjectPath(ctx, projectPath, opts)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Skipping %s: %v", projectPath, err))
+			continue
+		}
+
+		paths = append(paths, resolvedPath)
+		targets = append(targets, absPath)
+	}
+
+	if len(projectPaths) > 0 && len(paths) == 0 {
+		utils.Error("None of the given paths could be opened")
+		return clierr.New(clierr.InvalidPath, "none of the given paths could be opened")
+	}
+
+	return startZed(zedPath, append(args, paths...), targets, opts)
+}
+
+// applyEnvScript runs the setup script and puts the variables it sets under the ones in Env.
+// A dry run doesn't run it, the script could do anything, so it's only listed in the plan.
+func applyEnvScript(opts *LaunchOptions) error {
+	if opts.EnvScript == "" {
+		return nil
+	}
+
+	if opts.plan != nil {
+		opts.plan.Script = opts.EnvScript
+		return nil
+	}
+
+	scriptEnv, err := environ.FromScript(opts.EnvScript)
+	if err != nil {
+		return err
+	}
+
+	utils.Debug("Env script %s set %d variables\n", opts.EnvScript, len(scriptEnv))
+	opts.Env = append(scriptEnv, opts.Env...)
+	return nil
+}
+
+// startZed starts Zed with the final argument list, waiting on it when asked to.
+// The targets are recorded in the history once Zed has started.
+func startZed(zedPath string, args []string, targets []string, opts LaunchOptions) error {
+	if opts.plan != nil {
+		opts.plan.complete(zedPath, args, opts.Env)
+		opts.plan.Write(os.Stdout)
+		return nil
+	}
+
+	cmd := exec.Command(zedPath, args...)
+	if len(opts.Env) > 0 {
+		cmd.Env = environ.Merge(os.Environ(), opts.Env)
+	}
+
+	if opts.Attach {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	} else {
+		if opts.LogPath != "" {
+			logFile, err := openLogFile(opts.LogPath)
+			if err != nil {
+				utils.Warning(fmt.Sprintf("Zed output won't be logged: %v", err))
+			} else {
+				defer logFile.Close()
+				cmd.Stdout = logFile
+				cmd.Stderr = logFile
+			}
+		}
+
+		// A waiting CLI stays in the same console, so Ctrl+C still reaches Zed.
+		if !opts.Wait {
+			detach(cmd)
+		}
+	}
 
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("unable to start Zed: %w", err)
+		utils.Error(fmt.Sprintf("Unable to start Zed: %v", err))
+		return clierr.New(clierr.ZedNotFound, "unable to start Zed: %w", err)
 	}
 
 	utils.Success("Zed opened successfully!!")
+
+	if opts.HistoryPath != "" && len(targets) > 0 {
+		if err := history.Record(opts.HistoryPath, targets, time.Now()); err != nil {
+			utils.Debugln(fmt.Sprintf("Could not record history: %v", err))
+		}
+	}
+
+	if opts.Wait {
+		// The running Zed has no way to tell us when the paths are closed, so there's nothing to wait on.
+		if opts.handoff {
+			utils.Warning("Zed is already running and took over the paths, --wait can't wait for them to be closed")
+			utils.Infoln("👉 Tip: Close Zed first to use it as $EDITOR or GIT_EDITOR.")
+		} else {
+			utils.Infoln("⏳ Waiting for Zed to close...")
+		}
+
+		return waitForZed(cmd)
+	}
+
 	return nil
 }
@@ -0,0 +1,71 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"zed-cli-win-unofficial/internal/pathutil"
+)
+
+// DefaultMarkers are the files and folders that mark the root of a project when none are configured.
+// A trailing slash means the marker has to be a folder.
+func DefaultMarkers() []string {
+	return []string{".git", ".zed/", "go.mod", "package.json", "Cargo.toml", "pyproject.toml"}
+}
+
+// FindRoot walks up from the folder holding path to the nearest folder containing one of the markers.
+// The user's home folder is never picked, a dotfiles repo there would otherwise swallow every stray file.
+// The walk stops at a location that doesn't respond, eg: a disconnected mapped drive.
+func FindRoot(ctx context.Context, path string, markers []string) (string, bool) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+
+	home, _ := os.UserHomeDir()
+	dir := filepath.Dir(absPath)
+
+	for {
+		if home == "" || !strings.EqualFold(filepath.Clean(home), dir) {
+			found, err := hasMarker(ctx, dir, markers)
+			if err != nil {
+				return "", false
+			}
+
+			if found {
+				return dir, true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+// hasMarker checks if dir contains any of the markers, failing when the location doesn't respond
+func hasMarker(ctx context.Context, dir string, markers []string) (bool, error) {
+	for _, marker := range markers {
+		wantDir := strings.HasSuffix(marker, "/") || strings.HasSuffix(marker, `\`)
+		name := strings.TrimRight(marker, `/\`)
+		if name == "" {
+			continue
+		}
+
+		info, err := pathutil.Probe(ctx, filepath.Join(dir, name))
+		if errors.Is(err, pathutil.ErrNotResponding) || errors.Is(err, context.Canceled) {
+			return false, err
+		}
+
+		if err == nil && (!wantDir || info.IsDir()) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
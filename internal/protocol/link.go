@@ -0,0 +1,155 @@
+package protocol
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Scheme is the URL scheme the CLI registers, eg: zed-cli://open?path=C:\code\api\main.go&line=12
+const Scheme string = "zed-cli"
+
+// maxLinkLength caps the size of a link, no real path gets anywhere near it
+const maxLinkLength = 4096
+
+// maxPosition caps line and column numbers
+const maxPosition = 10_000_000
+
+// Link is a parsed `zed-cli://open` link
+type Link struct {
+	// Path is the absolute local path to open
+	Path string
+	// Line is the 1-based line to jump to, 0 when not set
+	Line int
+	// Column is the 1-based column to jump to, 0 when not set
+	Column int
+}
+
+// Parse validates a `zed-cli://open?path=...&line=..&col=..` link.
+// Links come from web pages, so anything beyond an absolute local path and a position is rejected:
+// unknown or repeated parameters, relative and network paths, and anything that could be read as a flag.
+func Parse(raw string) (Link, error) {
+	if len(raw) > maxLinkLength {
+		return Link{}, fmt.Errorf("link is too long")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link: %w", err)
+	}
+
+	if !strings.EqualFold(u.Scheme, Scheme) || u.Opaque != "" {
+		return Link{}, fmt.Errorf("not a %s:// link", Scheme)
+	}
+
+	if !strings.EqualFold(u.Host, "open") || (u.Path != "" && u.Path != "/") || u.User != nil || u.Fragment != "" {
+		return Link{}, fmt.Errorf("unsupported link, expected %s://open?path=...", Scheme)
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link parameters: %w", err)
+	}
+
+	for key, values := range query {
+		if key != "path" && key != "line" && key != "col" {
+			return Link{}, fmt.Errorf("unknown link parameter '%s'", key)
+		}
+
+		if len(values) != 1 {
+			return Link{}, fmt.Errorf("link parameter '%s' must be given once", key)
+		}
+	}
+
+	path, err := validatePath(query.Get("path"))
+	if err != nil {
+		return Link{}, err
+	}
+
+	link := Link{Path: path}
+
+	if link.Line, err = parsePosition(query, "line"); err != nil {
+		return Link{}, err
+	}
+
+	if link.Column, err = parsePosition(query, "col"); err != nil {
+		return Link{}, err
+	}
+
+	if link.Column > 0 && link.Line == 0 {
+		return Link{}, fmt.Errorf("link has a column but no line")
+	}
+
+	return link, nil
+}
+
+// Target formats the link as a launch target, `path:line:col` when it has a position
+func (l Link) Target() string {
+	switch {
+	case l.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", l.Path, l.Line, l.Column)
+	case l.Line > 0:
+		return fmt.Sprintf("%s:%d", l.Path, l.Line)
+	default:
+		return l.Path
+	}
+}
+
+// validatePath only accepts an absolute path on a local drive, eg: C:\code\api
+func validatePath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("link has no path")
+	}
+
+	for _, r := range path {
+		if unicode.IsControl(r) {
+			return "", fmt.Errorf("link path contains control characters")
+		}
+	}
+
+	if strings.HasPrefix(path, "-") {
+		return "", fmt.Errorf("link path cannot start with '-'")
+	}
+
+	// A drive letter followed by a separator. This also rules out UNC paths (\\server\share), which would
+	// make Windows reach out to a server named by the web page.
+	if len(path) < 3 || !isDriveLetter(path[0]) || path[1] != ':' || (path[2] != '\\' && path[2] != '/') {
+		return "", fmt.Errorf("link path must be an absolute local path, eg: C:\\code\\project")
+	}
+
+	// Past the drive, a colon could only smuggle in a position or an alternate data stream.
+	if strings.ContainsAny(path[2:], `:*?"<>|`) {
+		return "", fmt.Errorf("link path contains characters not allowed in Windows paths")
+	}
+
+	return filepath.Clean(path), nil
+}
+
+// parsePosition reads an optional line or column number
+func parsePosition(query url.Values, key string) (int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("link %s must be a number", key)
+		}
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > maxPosition {
+		return 0, fmt.Errorf("link %s is out of range", key)
+	}
+
+	return n, nil
+}
+
+// isDriveLetter checks if the byte is an ASCII letter
+func isDriveLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
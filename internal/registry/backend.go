@@ -0,0 +1,199 @@
+package registry
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// Backend applies registry changes under HKEY_CURRENT_USER, so they can be printed instead of made
+type Backend interface {
+	// CreateKey creates the key (and any missing parents) when it doesn't exist yet
+	CreateKey(path string) error
+	// SetString creates the key if needed and sets a string value on it, an empty name is the default value
+	SetString(path string, name string, value string) error
+	// DeleteKey removes the key with everything below it, a missing key is not an error
+	DeleteKey(path string)
+	// DeleteValue removes a value from the key, a missing key or value is not an error
+	DeleteValue(path string, name string)
+}
+
+// systemBackend writes to the real registry
+type systemBackend struct{}
+
+func (systemBackend) CreateKey(path string) error {
+	key, _, err := ensureKey(registry.CURRENT_USER, path, registry.WRITE)
+	if err != nil {
+		return err
+	}
+
+	return key.Close()
+}
+
+func (systemBackend) SetString(path string, name string, value string) error {
+	key, _, err := ensureKey(registry.CURRENT_USER, path, registry.WRITE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	return setStringValue(key, name, value)
+}
+
+func (systemBackend) DeleteKey(path string) {
+	DeleteKeyRecursively(registry.CURRENT_USER, path)
+}
+
+func (systemBackend) DeleteValue(path string, name string) {
+	DeleteValueSilently(registry.CURRENT_USER, path, name)
+}
+
+// registryState reads the registry as it is, which a dry run compares its changes against
+type registryState interface {
+	// KeyExists checks if the key exists
+	KeyExists(path string) bool
+	// StringValue returns a value of the key, ok is false when the key or value doesn't exist
+	StringValue(path string, name string) (value string, ok bool)
+}
+
+// systemState reads the real registry
+type systemState struct{}
+
+func (systemState) KeyExists(path string) bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, path, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+
+	key.Close()
+	return true
+}
+
+func (systemState) StringValue(path string, name string) (string, bool) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, path, registry.QUERY_VALUE)
+	if err != nil {
+		return "", false
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(name)
+	if err != nil {
+		return "", false
+	}
+
+	return value, true
+}
+
+// dryRunBackend prints every change instead of making it, labelled by what it would do to the registry as it is
+type dryRunBackend struct {
+	w     io.Writer
+	state registryState
+	// keys are the keys the dry run would have created so far, by lower-cased path
+	keys map[string]bool
+	// values are the values the dry run would have set so far, by lower-cased path and name
+	values map[string]string
+}
+
+// NewDryRunBackend creates a Backend that prints one line per change to w.
+// Each line says what the change would do, given what's in the registry now:
+//
+//	CREATE KEY   HKCU\Software\Classes\zed-cli
+//	KEEP KEY     HKCU\Software\Classes\.json
+//	CREATE VALUE HKCU\Software\Classes\zed-cli [URL Protocol] =
+//	CHANGE VALUE HKCU\Software\Classes\zed-cli [(Default)] = URL:Zed CLI Protocol (was URL:Zed)
+//	KEEP VALUE   HKCU\Software\Classes\.json\OpenWithProgids [ZedByUnofficialZedCLI.json] =
+//	DELETE KEY   HKCU\Software\Classes\zed-cli
+//	DELETE VALUE HKCU\Software\Classes\.json [(Default)]
+//	SKIP KEY     HKCU\Software\Classes\ZedByUnofficialZedCLI.txt (not present)
+//	SKIP VALUE   HKCU\Software\Classes\.txt [(Default)] (not present)
+func NewDryRunBackend(w io.Writer) Backend {
+	return newDryRunBackend(w, systemState{})
+}
+
+// newDryRunBackend creates the dry run Backend on top of any registry state
+func newDryRunBackend(w io.Writer, state registryState) *dryRunBackend {
+	return &dryRunBackend{w: w, state: state, keys: map[string]bool{}, values: map[string]string{}}
+}
+
+func (b *dryRunBackend) CreateKey(path string) error {
+	if b.keyExists(path) {
+		b.print("KEEP KEY", "HKCU\\%s", path)
+		return nil
+	}
+
+	b.print("CREATE KEY", "HKCU\\%s", path)
+	b.keys[strings.ToLower(path)] = true
+	return nil
+}
+
+func (b *dryRunBackend) SetString(path string, name string, value string) error {
+	if !b.keyExists(path) {
+		b.CreateKey(path)
+	}
+
+	current, ok := b.stringValue(path, name)
+	switch {
+	case !ok:
+		b.print("CREATE VALUE", "HKCU\\%s [%s] = %s", path, valueName(name), value)
+	case current != value:
+		b.print("CHANGE VALUE", "HKCU\\%s [%s] = %s (was %s)", path, valueName(name), value, current)
+	default:
+		b.print("KEEP VALUE", "HKCU\\%s [%s] = %s", path, valueName(name), value)
+	}
+
+	b.values[valueKey(path, name)] = value
+	return nil
+}
+
+func (b *dryRunBackend) DeleteKey(path string) {
+	if !b.keyExists(path) {
+		b.print("SKIP KEY", "HKCU\\%s (not present)", path)
+		return
+	}
+
+	b.print("DELETE KEY", "HKCU\\%s", path)
+}
+
+func (b *dryRunBackend) DeleteValue(path string, name string) {
+	if _, ok := b.stringValue(path, name); !ok {
+		b.print("SKIP VALUE", "HKCU\\%s [%s] (not present)", path, valueName(name))
+		return
+	}
+
+	b.print("DELETE VALUE", "HKCU\\%s [%s]", path, valueName(name))
+}
+
+// keyExists checks the registry, and the keys the dry run would have created before
+func (b *dryRunBackend) keyExists(path string) bool {
+	return b.keys[strings.ToLower(path)] || b.state.KeyExists(path)
+}
+
+// stringValue reads the registry, or the value the dry run would have set before
+func (b *dryRunBackend) stringValue(path string, name string) (string, bool) {
+	if value, ok := b.values[valueKey(path, name)]; ok {
+		return value, true
+	}
+
+	return b.state.StringValue(path, name)
+}
+
+// print writes a line with the action padded to a fixed width, so the lines can be compared as text
+func (b *dryRunBackend) print(action string, format string, args ...any) {
+	fmt.Fprintf(b.w, "%-12s %s\n", action, fmt.Sprintf(format, args...))
+}
+
+// valueKey identifies a value of a key, ignoring case the way the registry does
+func valueKey(path string, name string) string {
+	return strings.ToLower(path) + "\x00" + strings.ToLower(name)
+}
+
+// valueName shows the default value the way regedit does
+func valueName(name string) string {
+	if name == "" {
+		return "(Default)"
+	}
+
+	return name
+}
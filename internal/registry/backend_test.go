@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+)
+
+// fakeState is a registry holding the given keys and values, values keyed by `path [name]`
+type fakeState struct {
+	keys   map[string]bool
+	values map[string]string
+}
+
+func (s fakeState) KeyExists(path string) bool {
+	return s.keys[path]
+}
+
+func (s fakeState) StringValue(path string, name string) (string, bool) {
+	value, ok := s.values[path+" ["+name+"]"]
+	return value, ok
+}
+
+func TestDryRunBackend(t *testing.T) {
+	state := fakeState{
+		keys: map[string]bool{`Software\Classes\.json`: true, `Software\Classes\zed-cli`: true},
+		values: map[string]string{
+			`Software\Classes\zed-cli []`:             "URL:Zed",
+			`Software\Classes\zed-cli [URL Protocol]`: "",
+		},
+	}
+
+	out := &strings.Builder{}
+	backend := newDryRunBackend(out, state)
+
+	backend.CreateKey(`Software\Classes\.json`)
+	backend.CreateKey(`Software\Classes\ZedByUnofficialZedCLI.json`)
+	backend.CreateKey(`Software\Classes\ZedByUnofficialZedCLI.json`)
+	backend.SetString(`Software\Classes\ZedByUnofficialZedCLI.json`, "", "JSON Source File (Zed)")
+	backend.SetString(`Software\Classes\zed-cli`, "", "URL:Zed CLI Protocol")
+	backend.SetString(`Software\Classes\zed-cli`, "URL Protocol", "")
+	backend.SetString(`Software\Classes\zed-cli\DefaultIcon`, "", `"C:\Zed\zed.exe"`)
+	backend.DeleteKey(`Software\Classes\zed-cli`)
+	backend.DeleteKey(`Software\Classes\ZedByUnofficialZedCLI.txt`)
+	backend.DeleteValue(`Software\Classes\zed-cli`, "URL Protocol")
+	backend.DeleteValue(`Software\Classes\.txt`, "")
+
+	want := strings.Join([]string{
+		`KEEP KEY     HKCU\Software\Classes\.json`,
+		`CREATE KEY   HKCU\Software\Classes\ZedByUnofficialZedCLI.json`,
+		`KEEP KEY     HKCU\Software\Classes\ZedByUnofficialZedCLI.json`,
+		`CREATE VALUE HKCU\Software\Classes\ZedByUnofficialZedCLI.json [(Default)] = JSON Source File (Zed)`,
+		`CHANGE VALUE HKCU\Software\Classes\zed-cli [(Default)] = URL:Zed CLI Protocol (was URL:Zed)`,
+		`KEEP VALUE   HKCU\Software\Classes\zed-cli [URL Protocol] = `,
+		`CREATE KEY   HKCU\Software\Classes\zed-cli\DefaultIcon`,
+		`CREATE VALUE HKCU\Software\Classes\zed-cli\DefaultIcon [(Default)] = "C:\Zed\zed.exe"`,
+		`DELETE KEY   HKCU\Software\Classes\zed-cli`,
+		`SKIP KEY     HKCU\Software\Classes\ZedByUnofficialZedCLI.txt (not present)`,
+		`DELETE VALUE HKCU\Software\Classes\zed-cli [URL Protocol]`,
+		`SKIP VALUE   HKCU\Software\Classes\.txt [(Default)] (not present)`,
+	}, "\n") + "\n"
+
+	if got := out.String(); got != want {
+		t.Errorf("dry run printed:\n%s\nwant:\n%s", got, want)
+	}
+}
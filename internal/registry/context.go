@@ -5,8 +5,6 @@ import (
 	"path/filepath"
 	"strings"
 	"zed-cli-win-unofficial/internal/utils"
-
-	"golang.org/x/sys/windows/registry"
 )
 
 // InstallGenericContextMenu installs the generic "Open with Zed" context menu entries
@@ -26,39 +24,43 @@ func InstallGenericContextMenu(config *RegistryConfig) error {
 		return fmt.Errorf("failed to install folder background context menu: %w", err)
 	}
 
+	// 4. URL protocol for links into the editor (zed-cli://open?path=...)
+	if config.URLScheme != "" && config.LauncherPath != "" {
+		if err := createURLProtocol(config); err != nil {
+			return fmt.Errorf("failed to register %s:// links: %w", config.URLScheme, err)
+		}
+	}
+
 	return nil
 }
 
 // createContextMenuEntry creates a context menu entry for a given file type
 func createContextMenuEntry(fileType string, config *RegistryConfig) error {
+	backend := config.backend()
 	shellKeyPath := filepath.Join("Software", "Classes", fileType, "shell", config.AppName+"ByUnofficialZedCLI")
 
 	// Create the shell key
-	shellKey, _, err := ensureKey(registry.CURRENT_USER, shellKeyPath, registry.WRITE)
-	if err != nil {
+	if err := backend.CreateKey(shellKeyPath); err != nil {
 		return fmt.Errorf("failed to set up context menu entry: %w", err)
 	}
-	defer shellKey.Close()
 
-	if err := setStringValue(shellKey, "", config.GenericMenuText); err != nil {
+	if err := backend.SetString(shellKeyPath, "", config.GenericMenuText); err != nil {
 		return fmt.Errorf("failed to set context menu text: %w", err)
 	}
 
 	iconPath := fmt.Sprintf(`"%s"`, config.ExecutablePath)
-	if err := setStringValue(shellKey, "Icon", iconPath); err != nil {
+	if err := backend.SetString(shellKeyPath, "Icon", iconPath); err != nil {
 		utils.Debug("Warning: failed to set icon for %s: %v\n", fileType, err)
 	}
 
 	// Create the command subkey
 	commandKeyPath := filepath.Join(shellKeyPath, "command")
-	commandKey, _, err := ensureKey(registry.CURRENT_USER, commandKeyPath, registry.WRITE)
-	if err != nil {
+	if err := backend.CreateKey(commandKeyPath); err != nil {
 		return fmt.Errorf("failed to configure context menu action: %w", err)
 	}
-	defer commandKey.Close()
 
 	commandValue := fmt.Sprintf(`"%s" "%%1"`, config.ExecutablePath)
-	if err := setStringValue(commandKey, "", commandValue); err != nil {
+	if err := backend.SetString(commandKeyPath, "", commandValue); err != nil {
 		return fmt.Errorf("failed to configure context menu action: %w", err)
 	}
 
@@ -67,60 +69,106 @@ func createContextMenuEntry(fileType string, config *RegistryConfig) error {
 
 // createDirectoryBackgroundContextMenu creates context menu for directory background
 func createDirectoryBackgroundContextMenu(config *RegistryConfig) error {
+	backend := config.backend()
 	shellKeyPath := filepath.Join("Software", "Classes", "Directory", "Background", "shell", config.AppName+"ByUnofficialZedCLI")
 
 	// Create the shell key
-	shellKey, _, err := ensureKey(registry.CURRENT_USER, shellKeyPath, registry.WRITE)
-	if err != nil {
+	if err := backend.CreateKey(shellKeyPath); err != nil {
 		return fmt.Errorf("failed to set up folder background context menu: %w", err)
 	}
-	defer shellKey.Close()
 
-	if err := setStringValue(shellKey, "", config.GenericMenuText); err != nil {
+	if err := backend.SetString(shellKeyPath, "", config.GenericMenuText); err != nil {
 		return fmt.Errorf("failed to set folder background context menu text: %w", err)
 	}
 
 	iconPath := fmt.Sprintf(`"%s"`, config.ExecutablePath)
-	if err := setStringValue(shellKey, "Icon", iconPath); err != nil {
+	if err := backend.SetString(shellKeyPath, "Icon", iconPath); err != nil {
 		utils.Debug("Warning: failed to set icon for directory background: %v\n", err)
 	}
 
 	// Create the command subkey
 	commandKeyPath := filepath.Join(shellKeyPath, "command")
-	commandKey, _, err := ensureKey(registry.CURRENT_USER, commandKeyPath, registry.WRITE)
-	if err != nil {
+	if err := backend.CreateKey(commandKeyPath); err != nil {
 		return fmt.Errorf("failed to configure folder background context menu action: %w", err)
 	}
-	defer commandKey.Close()
 
 	// Set the command - for directory background, use %V% which represents the current directory
 	commandValue := fmt.Sprintf(`"%s" "%%V"`, config.ExecutablePath)
-	if err := setStringValue(commandKey, "", commandValue); err != nil {
+	if err := backend.SetString(commandKeyPath, "", commandValue); err != nil {
 		return fmt.Errorf("failed to configure folder background context menu action: %w", err)
 	}
 
 	return nil
 }
 
+// createURLProtocol registers the URL scheme so its links are opened by the CLI
+func createURLProtocol(config *RegistryConfig) error {
+	backend := config.backend()
+	protocolKeyPath := filepath.Join("Software", "Classes", config.URLScheme)
+
+	if err := backend.CreateKey(protocolKeyPath); err != nil {
+		return err
+	}
+
+	if err := backend.SetString(protocolKeyPath, "", "URL:"+config.AppName+" CLI Protocol"); err != nil {
+		return err
+	}
+
+	// An empty "URL Protocol" value is what marks the key as a URL scheme
+	if err := backend.SetString(protocolKeyPath, "URL Protocol", ""); err != nil {
+		return err
+	}
+
+	iconKeyPath := filepath.Join(protocolKeyPath, "DefaultIcon")
+	if err := backend.CreateKey(iconKeyPath); err != nil {
+		return err
+	}
+
+	if err := backend.SetString(iconKeyPath, "", fmt.Sprintf(`"%s"`, config.ExecutablePath)); err != nil {
+		utils.Debug("Warning: failed to set icon for %s:// links: %v\n", config.URLScheme, err)
+	}
+
+	commandKeyPath := filepath.Join(protocolKeyPath, "shell", "open", "command")
+	if err := backend.CreateKey(commandKeyPath); err != nil {
+		return err
+	}
+
+	// The link is passed as a single quoted argument and validated by open-url before anything is launched
+	commandValue := fmt.Sprintf(`"%s" open-url "%%1"`, config.LauncherPath)
+	return backend.SetString(commandKeyPath, "", commandValue)
+}
+
 // UninstallAllContextMenus removes all Zed context menu entries
 func UninstallAllContextMenus(config *RegistryConfig) error {
+	backend := config.backend()
+
 	// Remove all file types context menu
-	DeleteKeyRecursively(registry.CURRENT_USER, filepath.Join("Software", "Classes", "*", "shell", config.AppName+"ByUnofficialZedCLI"))
+	backend.DeleteKey(filepath.Join("Software", "Classes", "*", "shell", config.AppName+"ByUnofficialZedCLI"))
 
 	// Remove directory context menu
-	DeleteKeyRecursively(registry.CURRENT_USER, filepath.Join("Software", "Classes", "Directory", "shell", config.AppName+"ByUnofficialZedCLI"))
+	backend.DeleteKey(filepath.Join("Software", "Classes", "Directory", "shell", config.AppName+"ByUnofficialZedCLI"))
 
 	// Remove directory background context menu
-	DeleteKeyRecursively(registry.CURRENT_USER, filepath.Join("Software", "Classes", "Directory", "Background", "shell", config.AppName+"ByUnofficialZedCLI"))
+	backend.DeleteKey(filepath.Join("Software", "Classes", "Directory", "Background", "shell", config.AppName+"ByUnofficialZedCLI"))
+
+	// Remove the URL protocol
+	if config.URLScheme != "" {
+		backend.DeleteKey(filepath.Join("Software", "Classes", config.URLScheme))
+	}
 
 	// Remove ProgIDs for each file extension
 	for _, ext := range config.FileExtensions {
 		if !strings.HasPrefix(ext, ".") {
 			continue
 		}
-		progID := fmt.Sprintf("%s%s", config.AppName+"ByUnofficialZedCLI", ext)
-		DeleteKeyRecursively(registry.CURRENT_USER, filepath.Join("Software", "Classes", progID))
-		DeleteValueSilently(registry.CURRENT_USER, filepath.Join("Software", "Classes", ext), "")
+		progID := config.ProgID(ext)
+		backend.DeleteKey(filepath.Join("Software", "Classes", progID))
+		backend.DeleteValue(filepath.Join("Software", "Classes", ext), "")
+
+		// The associations, including the ones older versions made with their ProgID
+		openWithPath := filepath.Join("Software", "Classes", ext, "OpenWithProgids")
+		backend.DeleteValue(openWithPath, progID)
+		backend.DeleteValue(openWithPath, config.legacyProgID(ext))
 	}
 
 	return nil
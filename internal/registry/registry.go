@@ -33,64 +33,61 @@ func setStringValue(key registry.Key, name string, value string) error {
 
 // CreateProgID creates a ProgID registry entry for a file extension
 func CreateProgID(registryConfig *RegistryConfig, ext string) error {
+	backend := registryConfig.backend()
+
 	// 1. Create root level ProgID (for eg: Zed.json)
-	progID := fmt.Sprintf("%s%s", registryConfig.AppName+"ByUnofficialZedCLI", ext)
+	progID := registryConfig.ProgID(ext)
 	progPath := filepath.Join("Software", "Classes", progID)
 
-	key, _, err := ensureKey(registry.CURRENT_USER, progPath, registry.WRITE)
-	if err != nil {
+	if err := backend.CreateKey(progPath); err != nil {
 		return err
 	}
-	defer key.Close()
 
 	fileTypeDescription := fmt.Sprintf(registryConfig.PerFileTypeDescriptionText, strings.ToUpper(strings.TrimPrefix(ext, ".")))
 
-	if err := setStringValue(key, "", fileTypeDescription); err != nil {
+	if err := backend.SetString(progPath, "", fileTypeDescription); err != nil {
 		return fmt.Errorf("failed to register %s file type: %w", ext, err)
 	}
 
-	if err := setStringValue(key, "AppUserModelID", registryConfig.AppUserModelId); err != nil {
+	if err := backend.SetString(progPath, "AppUserModelID", registryConfig.AppUserModelId); err != nil {
 		return fmt.Errorf("failed to configure %s file type: %w", ext, err)
 	}
 
 	// 2. Add DefaultIcon Key with its value
 	defaultIconPath := filepath.Join(progPath, "DefaultIcon")
-	defaultIconKey, _, err := ensureKey(registry.CURRENT_USER, defaultIconPath, registry.WRITE)
-
-	if err != nil {
+	if err := backend.CreateKey(defaultIconPath); err != nil {
 		return fmt.Errorf("failed to set icon for %s files: %w", ext, err)
-
 	}
-	defer defaultIconKey.Close()
 
 	defaultIconValue := fmt.Sprintf(`"%s"`, registryConfig.ExecutablePath)
-	if err := setStringValue(defaultIconKey, "", defaultIconValue); err != nil {
+	if err := backend.SetString(defaultIconPath, "", defaultIconValue); err != nil {
 		return fmt.Errorf("failed to set icon for %s files: %w", ext, err)
 	}
 
 	// 3. Adding Shell > Open Key with Icon key/value entry
 	openKeyPath := filepath.Join(progPath, "shell", "open")
-	openKey, _, err := ensureKey(registry.CURRENT_USER, openKeyPath, registry.WRITE)
-	if err != nil {
+	if err := backend.CreateKey(openKeyPath); err != nil {
 		return fmt.Errorf("failed to configure %s file opening: %w", ext, err)
 	}
-	defer openKey.Close()
 
-	if err := setStringValue(openKey, "Icon", defaultIconValue); err != nil {
+	if err := backend.SetString(openKeyPath, "Icon", defaultIconValue); err != nil {
 		return fmt.Errorf("failed to set icon for %s files: %w", ext, err)
 	}
 
 	// 4. Adding Shell > Open > Command entry with DefaultValue of exe path
 	commandKeyPath := filepath.Join(openKeyPath, "command")
-	commandKey, _, err := ensureKey(registry.CURRENT_USER, commandKeyPath, registry.WRITE)
-
-	if err != nil {
+	if err := backend.CreateKey(commandKeyPath); err != nil {
 		return fmt.Errorf("failed to configure %s file opening: %w", ext, err)
 	}
 
-	defer commandKey.Close()
-	commandKeyValue := fmt.Sprintf(`"%s" "%%1"`, registryConfig.ExecutablePath)
-	if err := setStringValue(commandKey, "", commandKeyValue); err != nil {
+	// Workspace files and the like are opened through the CLI, which knows how to expand them for Zed.
+	commandTarget := registryConfig.ExecutablePath
+	if registryConfig.opensWithLauncher(ext) {
+		commandTarget = registryConfig.LauncherPath
+	}
+
+	commandKeyValue := fmt.Sprintf(`"%s" "%%1"`, commandTarget)
+	if err := backend.SetString(commandKeyPath, "", commandKeyValue); err != nil {
 		return fmt.Errorf("failed to configure %s file opening: %w", ext, err)
 	}
 
@@ -98,24 +95,44 @@ func CreateProgID(registryConfig *RegistryConfig, ext string) error {
 }
 
 // AssociateExtensionWithProgID: associates a file extension with its ProgID
-func AssociateExtensionWithProgID(ext string, progID string) error {
+func AssociateExtensionWithProgID(registryConfig *RegistryConfig, ext string, progID string) error {
+	backend := registryConfig.backend()
 	extKeyPath := filepath.Join("Software", "Classes", ext, "OpenWithProgids")
-	extKey, _, err := ensureKey(registry.CURRENT_USER, extKeyPath, registry.WRITE)
 
-	if err != nil {
+	if err := backend.CreateKey(extKeyPath); err != nil {
 		return fmt.Errorf("failed to access %s file type settings: %w", ext, err)
 	}
 
-	defer extKey.Close()
-
-	if err := setStringValue(extKey, progID, ""); err != nil {
+	if err := backend.SetString(extKeyPath, progID, ""); err != nil {
 		return fmt.Errorf("failed to associate %s files with Zed: %w", ext, err)
 	}
 
+	// Older versions associated the extension with a ProgID that was never created, which is replaced here.
+	if legacyProgID := registryConfig.legacyProgID(ext); !strings.EqualFold(legacyProgID, progID) {
+		backend.DeleteValue(extKeyPath, legacyProgID)
+	}
+
 	utils.Debug("File type %s associated with Zed\n", ext)
 	return nil
 }
 
+// SetDefaultProgID makes the ProgID the default handler of an extension, for file types that belong to the CLI
+func SetDefaultProgID(registryConfig *RegistryConfig, ext string, progID string) error {
+	backend := registryConfig.backend()
+	extKeyPath := filepath.Join("Software", "Classes", ext)
+
+	if err := backend.CreateKey(extKeyPath); err != nil {
+		return fmt.Errorf("failed to access %s file type settings: %w", ext, err)
+	}
+
+	if err := backend.SetString(extKeyPath, "", progID); err != nil {
+		return fmt.Errorf("failed to make Zed the default for %s files: %w", ext, err)
+	}
+
+	utils.Debug("Zed is now the default for %s files\n", ext)
+	return nil
+}
+
 // DeleteKeyRecursivly deletes a registry key and all its subkey
 func DeleteKeyRecursively(baseKey registry.Key, path string) {
 	// Step 1: Open the Key
@@ -1,5 +1,12 @@
 package registry
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"zed-cli-win-unofficial/internal/pathutil"
+)
+
 type RegistryConfig struct {
 	// AppName is the display name of the application in the Windows registry
 	AppName string
@@ -13,15 +20,48 @@ type RegistryConfig struct {
 	FileExtensions []string
 	// PerFileTypeDescTmpl is a template string for describing file types in the registry
 	PerFileTypeDescriptionText string
+	// LauncherPath is the full path to this CLI, which opens the file types zed.exe can't handle itself
+	LauncherPath string
+	// LauncherExtensions are the file extensions opened through LauncherPath instead of zed.exe, eg: .zed-workspace
+	LauncherExtensions []string
+	// URLScheme is the URL protocol whose links are handed to `LauncherPath open-url`, eg: zed-cli
+	URLScheme string
+	// Backend applies the registry changes, the real registry when nil
+	Backend Backend
 }
 
 func NewConfig(executablePath string, extensions []string) *RegistryConfig {
 	return &RegistryConfig{
 		AppName:                    "Zed",
-		ExecutablePath:             executablePath,
-		AppUserModelId:             executablePath,
+		ExecutablePath:             pathutil.Display(executablePath),
+		AppUserModelId:             pathutil.Display(executablePath),
 		GenericMenuText:            "Open w&ith Zed",
 		FileExtensions:             extensions,
 		PerFileTypeDescriptionText: "%s Source File (Zed)",
 	}
 }
+
+// ProgID returns the ProgID the given file extension is registered under, eg: ZedByUnofficialZedCLI.json
+func (c *RegistryConfig) ProgID(ext string) string {
+	return fmt.Sprintf("%s%s", c.AppName+"ByUnofficialZedCLI", ext)
+}
+
+// legacyProgID is the ProgID older versions associated extensions with, eg: Zed.json.
+// No ProgID key was ever created under that name, so associations pointing at it are dead.
+func (c *RegistryConfig) legacyProgID(ext string) string {
+	return c.AppName + ext
+}
+
+// opensWithLauncher checks if files with the extension are opened through the CLI rather than zed.exe
+func (c *RegistryConfig) opensWithLauncher(ext string) bool {
+	return c.LauncherPath != "" && slices.ContainsFunc(c.LauncherExtensions, func(e string) bool { return strings.EqualFold(e, ext) })
+}
+
+// backend returns the Backend changes go through
+func (c *RegistryConfig) backend() Backend {
+	if c.Backend == nil {
+		return systemBackend{}
+	}
+
+	return c.Backend
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Confirm asks a yes/no question on the terminal, anything but y/yes (including no input at all) counts as no
+func Confirm(question string) bool {
+	fmt.Printf("❓ %s [y/N]: ", question)
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// Prompt asks an open question on the terminal and returns the trimmed answer, empty when there was no input
+func Prompt(question string) string {
+	fmt.Printf("❓ %s: ", question)
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer)
+}
@@ -0,0 +1,112 @@
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Extension is the file extension of workspace files
+const Extension string = ".zed-workspace"
+
+// Workspace describes a set of folders and files that are opened together, eg:
+//
+//	{
+//	  "folders": ["api", "../shared/web"],
+//	  "files": ["api/README.md:10"],
+//	  "window": "new",
+//	  "zedArgs": ["--foreground"],
+//	  "env": {"GOFLAGS": "-tags=dev"}
+//	}
+//
+// Relative paths are relative to the folder holding the workspace file, so it can be committed and shared.
+type Workspace struct {
+	// Path is the absolute path of the workspace file
+	Path string `json:"-"`
+	// Folders are opened as the roots of the workspace
+	Folders []string `json:"folders"`
+	// Files are opened after the folders and get the focus, `path:line:col` is allowed
+	Files []string `json:"files,omitempty"`
+	// Window is where the workspace opens: new, add or reuse (Zed decides when empty)
+	Window string `json:"window,omitempty"`
+	// ZedArgs are forwarded to zed.exe, like the arguments after `--`
+	ZedArgs []string `json:"zedArgs,omitempty"`
+	// Env holds extra environment variables for Zed
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// IsWorkspaceFile checks if the path has the workspace file extension
+func IsWorkspaceFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), Extension)
+}
+
+// Load reads the workspace file at path and makes its paths absolute
+func Load(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read workspace: %w", err)
+	}
+
+	// Unknown keys are rejected so a typo like "folder" doesn't silently open nothing.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var ws Workspace
+	if err := decoder.Decode(&ws); err != nil {
+		return nil, fmt.Errorf("invalid workspace %s: %w", path, err)
+	}
+
+	if len(ws.Folders) == 0 && len(ws.Files) == 0 {
+		return nil, fmt.Errorf("invalid workspace %s: no folders or files listed", path)
+	}
+
+	if !slices.Contains([]string{"", "new", "add", "reuse"}, ws.Window) {
+		return nil, fmt.Errorf("invalid workspace %s: unknown window '%s' (expected new, add or reuse)", path, ws.Window)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve path: %w", err)
+	}
+
+	ws.Path = absPath
+	dir := filepath.Dir(absPath)
+	ws.Folders = resolveAll(dir, ws.Folders)
+	ws.Files = resolveAll(dir, ws.Files)
+
+	return &ws, nil
+}
+
+// Targets returns the launch targets of the workspace: the folders first, then the files
+func (w *Workspace) Targets() []string {
+	return append(slices.Clone(w.Folders), w.Files...)
+}
+
+// EnvAssignments returns the workspace env as `KEY=VALUE` assignments, in a stable order
+func (w *Workspace) EnvAssignments() []string {
+	assignments := make([]string, 0, len(w.Env))
+	for key, value := range w.Env {
+		assignments = append(assignments, key+"="+value)
+	}
+
+	slices.Sort(assignments)
+	return assignments
+}
+
+// resolveAll makes every path absolute against dir, a `:line:col` suffix stays on the end
+func resolveAll(dir string, paths []string) []string {
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		resolved = append(resolved, path)
+	}
+
+	return resolved
+}
@@ -0,0 +1,105 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeWorkspace writes a workspace file into a temp folder and returns its path
+func writeWorkspace(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "team"+Extension)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	absolute := filepath.Join(t.TempDir(), "shared")
+	path := writeWorkspace(t, `{
+		"folders": ["api", "../infra", `+strings.ReplaceAll(`"`+absolute+`"`, `\`, `\\`)+`],
+		"files": ["api/README.md:10"],
+		"window": "new",
+		"zedArgs": ["--foreground"],
+		"env": {"GOFLAGS": "-tags=dev", "A": "1"}
+	}`)
+	dir := filepath.Dir(path)
+
+	ws, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	if ws.Path != path {
+		t.Errorf("Path = %q, want %q", ws.Path, path)
+	}
+
+	wantFolders := []string{filepath.Join(dir, "api"), filepath.Join(filepath.Dir(dir), "infra"), absolute}
+	if !slices.Equal(ws.Folders, wantFolders) {
+		t.Errorf("Folders = %q, want %q", ws.Folders, wantFolders)
+	}
+
+	wantTargets := append(wantFolders, filepath.Join(dir, "api", "README.md:10"))
+	if got := ws.Targets(); !slices.Equal(got, wantTargets) {
+		t.Errorf("Targets() = %q, want %q", got, wantTargets)
+	}
+
+	if ws.Window != "new" || !slices.Equal(ws.ZedArgs, []string{"--foreground"}) {
+		t.Errorf("Window, ZedArgs = %q, %q; want new, [--foreground]", ws.Window, ws.ZedArgs)
+	}
+
+	if got := ws.EnvAssignments(); !slices.Equal(got, []string{"A=1", "GOFLAGS=-tags=dev"}) {
+		t.Errorf("EnvAssignments() = %q, want them sorted", got)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown key", `{"folder": ["api"]}`, "unknown field"},
+		{"nothing to open", `{"folders": [], "window": "new"}`, "no folders or files"},
+		{"unknown window", `{"folders": ["api"], "window": "tab"}`, "unknown window"},
+		{"not json", `folders: api`, "invalid workspace"},
+		{"wrong type", `{"folders": "api"}`, "invalid workspace"},
+		{"path key", `{"folders": ["api"], "Path": "C:\\evil"}`, "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(writeWorkspace(t, tt.content)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing"+Extension)); err == nil {
+		t.Error("Load() of a missing file = nil, want an error")
+	}
+}
+
+func TestIsWorkspaceFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: `C:\code\team.zed-workspace`, want: true},
+		{path: `C:\code\TEAM.ZED-WORKSPACE`, want: true},
+		{path: `C:\code\team.code-workspace`, want: false},
+		{path: `C:\code\zed-workspace`, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsWorkspaceFile(tt.path); got != tt.want {
+			t.Errorf("IsWorkspaceFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}